@@ -18,8 +18,10 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type DSPASpec struct {
@@ -43,11 +45,16 @@ type DSPASpec struct {
 	// +kubebuilder:default:="v2"
 	DSPVersion string `json:"dspVersion,omitempty"`
 
-	// PodToPodTLS Set to "true" or "false" to enable or disable TLS communication between DSPA components (pods). Defaults to "true" to enable TLS between all pods. Only supported in DSP V2 on OpenShift.
+	// PodToPodTLS Set to "true" or "false" to enable or disable TLS communication between DSPA components (pods). Defaults to "true" to enable TLS between all pods. Only supported in DSP V2, on OpenShift or with the CertManager tls.certificateProvider.
+	// The TLS security profile of the cluster is applied to the kube-rbac-proxy and MLMD Envoy listeners. The KFP API server listener keeps its default TLS versions and cipher suites, since the API server has no settings for them.
 	// +kubebuilder:default:=true
 	// +kubebuilder:validation:Optional
 	PodToPodTLS *bool `json:"podToPodTLS"`
 
+	// TLS configures how the serving certificates of the DSPA components are issued.
+	// +kubebuilder:validation:Optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
 	// WorkflowController is an argo-specific component that manages a DSPA's Workflow objects and handles the orchestration of them with the central Argo server
 	// +kubebuilder:validation:Optional
 	*WorkflowController `json:"workflowController,omitempty"`
@@ -56,11 +63,120 @@ type DSPASpec struct {
 	// +kubebuilder:validation:Optional
 	Proxy *ProxyConfig `json:"proxy,omitempty"`
 
+	// NetworkPolicy customizes the NetworkPolicies of the DSPA components.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
 	// MLflow configuration for the API server MLflow plugin.
 	// Omitting this field defaults to integrationMode AUTODETECT and injectUserEnvVars false.
 	// Set integrationMode to DISABLED to opt out of MLflow integration.
 	// +kubebuilder:validation:Optional
 	MLflow *MLflowConfig `json:"mlflow,omitempty"`
+
+	// CredentialRotation periodically regenerates the database password and object storage keys that the operator
+	// generates for its own MariaDB and Minio deployments. Credentials supplied through user provided secrets are never rotated.
+	// The Minio keys are reset by restarting Minio with the new keys, so object storage requests fail while it restarts.
+	// Since the pipeline pods keep the keys they started with, the Minio keys are not rotated while pipeline runs are
+	// active in the namespace. Only the components using the rotated credentials are restarted.
+	// +kubebuilder:validation:Optional
+	CredentialRotation *CredentialRotation `json:"credentialRotation,omitempty"`
+
+	// PodPlacement is the default scheduling configuration of all DSPA component pods. The podPlacement of a component
+	// overrides it field by field.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+
+	// Labels added to every resource the operator deploys for this DSPA, e.g. for cost allocation. Labels set by
+	// the operator take precedence.
+	// +kubebuilder:validation:Optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+	// Annotations added to every resource the operator deploys for this DSPA. Annotations set by the operator take
+	// precedence.
+	// +kubebuilder:validation:Optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+	// Labels added to the pods of every DSPA component. Labels set by the operator take precedence.
+	// +kubebuilder:validation:Optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// Annotations added to the pods of every DSPA component. Annotations set by the operator take precedence.
+	// +kubebuilder:validation:Optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Unrestricted;Restricted
+type NetworkPolicyEgressMode string
+
+const (
+	NetworkPolicyEgressUnrestricted NetworkPolicyEgressMode = "Unrestricted"
+	NetworkPolicyEgressRestricted   NetworkPolicyEgressMode = "Restricted"
+)
+
+type NetworkPolicyConfig struct {
+	// Sources allowed to reach the API Server, MLMD and MariaDB on top of the ones allowed by default, e.g. a
+	// monitoring namespace.
+	// +kubebuilder:validation:Optional
+	AdditionalIngressPeers []networkingv1.NetworkPolicyPeer `json:"additionalIngressPeers,omitempty"`
+	// Unrestricted does not restrict the egress traffic of the DSPA components. Restricted denies it, except to the
+	// cluster DNS, the Kubernetes API server endpoints, the pods of the namespace, the database, the object storage,
+	// the MLflow Service and the additionalEgressPeers. A NetworkPolicy cannot select a DNS name, so when the external
+	// database, object storage or MLflow host is not an IP address its port is only allowed to the
+	// additionalEgressPeers, which are then required and must select its addresses. They are also required to reach
+	// the AWS STS endpoint with the WebIdentity credentialsMode and the Google OAuth endpoint with the gcs provider.
+	// The azure provider authenticates with the storage account key and does not reach Azure AD. Default: Unrestricted
+	// +kubebuilder:default:=Unrestricted
+	// +kubebuilder:validation:Optional
+	EgressMode NetworkPolicyEgressMode `json:"egressMode,omitempty"`
+	// Destinations the DSPA components are allowed to reach when the egressMode is Restricted, including the
+	// addresses of the external database and object storage hosts that are not IP addresses.
+	// +kubebuilder:validation:Optional
+	AdditionalEgressPeers []networkingv1.NetworkPolicyPeer `json:"additionalEgressPeers,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ServiceCA;CertManager
+type CertificateProvider string
+
+const (
+	CertificateProviderServiceCA   CertificateProvider = "ServiceCA"
+	CertificateProviderCertManager CertificateProvider = "CertManager"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.certificateProvider) || self.certificateProvider != 'CertManager' || has(self.certManager)",message="certManager must be specified for the CertManager certificateProvider"
+type TLSConfig struct {
+	// ServiceCA issues the certificates with the OpenShift service-ca operator. CertManager issues them with
+	// cert-manager Certificates, which makes pod to pod TLS available on clusters without the service-ca operator.
+	// Default: ServiceCA
+	// +kubebuilder:default:=ServiceCA
+	// +kubebuilder:validation:Optional
+	CertificateProvider CertificateProvider `json:"certificateProvider,omitempty"`
+	// +kubebuilder:validation:Optional
+	CertManager *CertManagerConfig `json:"certManager,omitempty"`
+}
+
+type CertManagerConfig struct {
+	// Issuer of the certificates. It must populate the ca.crt key of the certificate Secrets, e.g. a CA Issuer, since
+	// the components trust the CA found there.
+	// +kubebuilder:validation:Required
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+type CertManagerIssuerReference struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Kind of the issuer, e.g. Issuer, in the namespace of the DSPA, or ClusterIssuer. Default: Issuer
+	// +kubebuilder:default:=Issuer
+	// +kubebuilder:validation:Optional
+	Kind string `json:"kind,omitempty"`
+	// API group of the issuer, set it for external issuers. Default: cert-manager.io
+	// +kubebuilder:default:=cert-manager.io
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+}
+
+type CredentialRotation struct {
+	// Interval between credential rotations, e.g. "2160h" for 90 days.
+	// +kubebuilder:default:="2160h"
+	// +kubebuilder:validation:Optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
 }
 
 // +kubebuilder:validation:Pattern=`^(Managed|Removed)$`
@@ -119,6 +235,10 @@ type APIServer struct {
 	// +kubebuilder:default:=true
 	// +kubebuilder:validation:Optional
 	EnableRoute bool `json:"enableOauth"`
+	// Expose this DSP API Server outside of the cluster with a Route, an Ingress or a Gateway API HTTPRoute. When
+	// set, it replaces enableOauth.
+	// +kubebuilder:validation:Optional
+	Exposure *Exposure `json:"exposure,omitempty"`
 	// Include the Iris sample pipeline with the deployment of this DSP API Server. Default: true
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:Optional
@@ -131,6 +251,20 @@ type APIServer struct {
 	ManagedPipelines *ManagedPipelinesSpec `json:"managedPipelines,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
+	// Number of API Server replicas. Ignored when highAvailability.autoscaling is set. Default: 1
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Run the API Server highly available, so that node drains and zone failures do not take pipelines offline.
+	// +kubebuilder:validation:Optional
+	HighAvailability *APIServerHighAvailability `json:"highAvailability,omitempty"`
 
 	// If the Object store/DB is behind a TLS secured connection that is
 	// unrecognized by the host OpenShift/K8s cluster, then you can
@@ -203,6 +337,123 @@ type APIServer struct {
 	// +kubebuilder:validation:Pattern=`^(?:(\d+(?:\.\d+)?h))?(?:(\d+(?:\.\d+)?m))?(?:(\d+(?:\.\d+)?s))?$`
 	// +kubebuilder:validation:MinLength=2
 	ResourceTTL *metav1.Duration `json:"resourceTTL,omitempty"`
+
+	// RunDefaults are applied to the Argo Workflow of every pipeline run, together with the ResourceTTL. They
+	// take precedence over the workflowDefaults of the WorkflowController config.
+	// +kubebuilder:validation:Optional
+	RunDefaults *RunDefaults `json:"runDefaults,omitempty"`
+}
+
+type RunDefaults struct {
+	// When the pods of the runs are deleted. Default: the pods are not deleted
+	// +kubebuilder:validation:Optional
+	PodGCStrategy PodGCStrategy `json:"podGCStrategy,omitempty"`
+	// Maximum duration of a run in seconds, after which its running steps are terminated and the run fails.
+	// Default: unlimited
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// Retry strategy of the steps of the runs that do not configure their own. Default: the steps are not retried
+	// +kubebuilder:validation:Optional
+	RetryStrategy *RunRetryStrategy `json:"retryStrategy,omitempty"`
+	// Partial pod spec patched into the pods of all the steps of the runs, e.g. to set a priorityClassName or a
+	// securityContext. It is validated as a pod spec when the DSPA is reconciled.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodSpecPatch *runtime.RawExtension `json:"podSpecPatch,omitempty"`
+	// Default pod settings of the steps of the runs. The settings a pipeline sets for its steps, e.g. with the
+	// kfp-kubernetes extension, take precedence, and the podSpecPatch is applied over both.
+	// +kubebuilder:validation:Optional
+	PodSpec *RunPodSpec `json:"podSpec,omitempty"`
+}
+
+type RunPodSpec struct {
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Service account the steps of the runs run as, replacing the pipeline runner service account of the DSPA.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// The security context schema is not embedded in the CRD to keep it within the size limits.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// +kubebuilder:validation:Optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Always;OnFailure;OnError;OnTransientError
+type RetryPolicy string
+
+const (
+	RetryPolicyAlways           RetryPolicy = "Always"
+	RetryPolicyOnFailure        RetryPolicy = "OnFailure"
+	RetryPolicyOnError          RetryPolicy = "OnError"
+	RetryPolicyOnTransientError RetryPolicy = "OnTransientError"
+)
+
+type RunRetryStrategy struct {
+	// Maximum number of retries of a step.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=0
+	Limit int32 `json:"limit"`
+	// Which failures of a step are retried. Default: OnFailure
+	// +kubebuilder:validation:Optional
+	RetryPolicy RetryPolicy `json:"retryPolicy,omitempty"`
+	// Delay between the retries of a step. Default: the steps are retried immediately
+	// +kubebuilder:validation:Optional
+	Backoff *RunRetryBackoff `json:"backoff,omitempty"`
+}
+
+type RunRetryBackoff struct {
+	// Delay before the first retry, e.g. "30s".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Type=string
+	Duration metav1.Duration `json:"duration"`
+	// Factor multiplying the delay after each retry. Default: 1
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Factor *int32 `json:"factor,omitempty"`
+	// Maximum total duration of the retries of a step, after which the step is not retried anymore.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || self.enabled",message="autoscaling requires highAvailability to be enabled"
+type APIServerHighAvailability struct {
+	// Enable the high availability preset: at least 2 replicas, a PodDisruptionBudget allowing one unavailable
+	// replica, and a preferred pod anti-affinity spreading the replicas across nodes. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled"`
+	// Scale the API Server with a HorizontalPodAutoscaler instead of a fixed number of replicas.
+	// +kubebuilder:validation:Optional
+	Autoscaling *APIServerAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+type APIServerAutoscaling struct {
+	// Default: 2
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=2
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Average CPU utilization, relative to the requested CPU, the autoscaler targets. Default: 80
+	// +kubebuilder:default:=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
 }
 
 type APIServerWorkspace struct {
@@ -239,6 +490,13 @@ type PersistenceAgent struct {
 	NumWorkers int `json:"numWorkers,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
 }
 
 type ScheduledWorkflow struct {
@@ -253,6 +511,13 @@ type ScheduledWorkflow struct {
 	CronScheduleTimezone string `json:"cronScheduleTimezone,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
 }
 
 type Database struct {
@@ -263,12 +528,12 @@ type Database struct {
 	// CustomExtraParams allow users to further customize the sql dsn parameters used by the Pipeline Server
 	// when opening a connection with the Database.
 	// ref: https://github.com/go-sql-driver/mysql?tab=readme-ov-file#dsn-data-source-name
+	// ref (postgres): https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-PARAMKEYWORDS
 	//
 	// Value must be a JSON string. For example, to disable tls for Pipeline Server DB connection
 	// the user can provide a string: {"tls":"true"}
 	//
-	// If updating post DSPA deployment, then a manual restart of the pipeline server pod will be required
-	// so the new configmap may be consumed.
+	// If updated post DSPA deployment, the pipeline server is restarted automatically to consume the new value.
 	CustomExtraParams *string `json:"customExtraParams,omitempty"`
 
 	// Default: false
@@ -301,6 +566,62 @@ type MariaDB struct {
 	StorageClassName string `json:"storageClassName,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
+	// Enable operator-managed scheduled backups of this MariaDB database.
+	// +kubebuilder:validation:Optional
+	Backup *MariaDBBackup `json:"backup,omitempty"`
+	// Restore the database from an existing backup before the API Server is deployed. The restore is only
+	// performed into an empty database, so it is safe to leave configured once the DSPA is running. The tables left
+	// by a failed restore attempt are dropped before it is retried.
+	// +kubebuilder:validation:Optional
+	RestoreFrom *MariaDBRestoreSource `json:"restoreFrom,omitempty"`
+}
+
+type MariaDBBackup struct {
+	// Cron schedule on which database backups are taken. Default: "0 2 * * *"
+	// +kubebuilder:default:="0 2 * * *"
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule,omitempty"`
+	// Number of most recent backups to keep, older backups are pruned after each successful backup. Default: 7
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	RetentionCount int32 `json:"retentionCount,omitempty"`
+	// Key prefix in the DSPA object storage bucket under which backups are uploaded.
+	// Ignored when PVCName is specified. Default: "backups/mariadb"
+	// +kubebuilder:validation:Optional
+	ObjectStoragePrefix string `json:"objectStoragePrefix,omitempty"`
+	// Name of an existing PersistentVolumeClaim to write backups to, instead of the DSPA object storage.
+	// +kubebuilder:validation:Optional
+	PVCName string `json:"pvcName,omitempty"`
+	// Specify a custom image used to upload backups to object storage. The image must provide the MinIO client (mc).
+	// Default: operator config Images.MariaDBBackup
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.objectStorageKey) != has(self.pvcName)",message="exactly one of objectStorageKey or pvcName must be specified"
+// +kubebuilder:validation:XValidation:rule="!has(self.pvcName) || has(self.path)",message="path must be specified when pvcName is specified"
+type MariaDBRestoreSource struct {
+	// Key of the SQL dump to restore, in the DSPA object storage bucket. e.g. "backups/mariadb/mlpipeline-20240101T020000Z.sql"
+	// +kubebuilder:validation:Optional
+	ObjectStorageKey string `json:"objectStorageKey,omitempty"`
+	// Name of an existing PersistentVolumeClaim containing the SQL dump to restore.
+	// +kubebuilder:validation:Optional
+	PVCName string `json:"pvcName,omitempty"`
+	// Path of the SQL dump within the PersistentVolumeClaim. Required when PVCName is specified.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+	// Specify a custom image used to download the dump from object storage. The image must provide the MinIO client (mc).
+	// Default: operator config Images.MariaDBBackup
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
 }
 
 type ExternalDB struct {
@@ -310,8 +631,17 @@ type ExternalDB struct {
 	Username       string          `json:"username"`
 	DBName         string          `json:"pipelineDBName"`
 	PasswordSecret *SecretKeyValue `json:"passwordSecret"`
+	// The SQL driver used to connect to the external database. Set to "postgres" to use a PostgreSQL
+	// database instead of MySQL/MariaDB. When using postgres, CustomExtraParams are passed as
+	// libpq connection parameters (e.g. {"sslmode":"verify-full"}). Default: mysql
+	// +kubebuilder:default:=mysql
+	// +kubebuilder:validation:Enum=mysql;postgres
+	// +kubebuilder:validation:Optional
+	Driver string `json:"driver,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.additionalStores) || !has(self.externalStorage) || !has(self.externalStorage.provider) || self.externalStorage.provider == 's3'",message="additionalStores require the s3 provider for externalStorage"
+// +kubebuilder:validation:XValidation:rule="!has(self.healthCheck) || !has(self.healthCheck.mode) || self.healthCheck.mode != 'ReadWrite' || !has(self.externalStorage) || !has(self.externalStorage.provider) || self.externalStorage.provider == 's3'",message="the ReadWrite healthCheck mode requires the s3 provider for externalStorage"
 type ObjectStorage struct {
 	// Enable DS Pipelines Operator management of Minio. Setting Deploy to false disables operator reconciliation.
 	*Minio           `json:"minio,omitempty"`
@@ -320,12 +650,61 @@ type ObjectStorage struct {
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:Optional
 	DisableHealthCheck bool `json:"disableHealthCheck"`
+	// Configure the object storage health check. Ignored when disableHealthCheck is true.
+	// +kubebuilder:validation:Optional
+	HealthCheck *ObjectStorageHealthCheck `json:"healthCheck,omitempty"`
+	// Additional s3 compatible stores for pipeline runs. Artifacts of runs with a pipeline root of
+	// s3://<bucket>/<pipelineRootPrefix> of a store are written to that store instead of the default object storage.
+	// Cannot be combined with apiServer.customKfpLauncherConfigMap.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	AdditionalStores []AdditionalObjectStore `json:"additionalStores,omitempty"`
 	// Enable an external route so the object storage is reachable from outside the cluster. Default: false
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:Optional
 	EnableExternalRoute bool `json:"enableExternalRoute"`
 }
 
+type AdditionalObjectStore struct {
+	// Unique name of the store, used to report its availability.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+	// +kubebuilder:validation:Optional
+	Port string `json:"port,omitempty"`
+	// Default: https
+	// +kubebuilder:default:=https
+	// +kubebuilder:validation:Enum=http;https
+	// +kubebuilder:validation:Optional
+	Scheme string `json:"scheme,omitempty"`
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
+	// +kubebuilder:validation:Required
+	Bucket string `json:"bucket"`
+	// Key prefix in the bucket the store is used for. Pipeline roots in the bucket outside of this prefix use the
+	// default object storage. The whole bucket when unset.
+	// +kubebuilder:validation:Optional
+	PipelineRootPrefix string `json:"pipelineRootPrefix,omitempty"`
+	// +kubebuilder:validation:Required
+	*S3CredentialSecret `json:"s3CredentialsSecret"`
+}
+
+type ObjectStorageHealthCheck struct {
+	// ReadOnly only verifies that the object store is reachable and accepts the credentials. ReadWrite additionally
+	// writes, reads back and deletes a probe object under the basePath of the bucket, verifying the permissions
+	// pipeline runs need. ReadWrite is only supported by the s3 provider.
+	// Default: ReadOnly
+	// +kubebuilder:default:=ReadOnly
+	// +kubebuilder:validation:Enum=ReadOnly;ReadWrite
+	// +kubebuilder:validation:Optional
+	Mode string `json:"mode,omitempty"`
+}
+
 type Minio struct {
 	// Enable DS Pipelines Operator management of Minio. Setting Deploy to false disables operator reconciliation. Default: true
 	// +kubebuilder:default:=true
@@ -344,6 +723,17 @@ type Minio struct {
 	StorageClassName string `json:"storageClassName,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
+	// Expose Minio outside of the cluster with a Route, an Ingress or a Gateway API HTTPRoute. When set, it replaces
+	// objectStorage.enableExternalRoute.
+	// +kubebuilder:validation:Optional
+	Exposure *Exposure `json:"exposure,omitempty"`
 	// Specify a custom image for Minio pod.
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
@@ -361,14 +751,32 @@ type MLMD struct {
 type Envoy struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 	Image     string                `json:"image,omitempty"`
+	// Specify the scheduling of the MLMD Envoy pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
 	// +kubebuilder:default:=true
 	// +kubebuilder:validation:Optional
 	DeployRoute bool `json:"deployRoute"`
+	// Expose the MLMD Envoy outside of the cluster with a Route, an Ingress or a Gateway API HTTPRoute. When set, it
+	// replaces deployRoute.
+	// +kubebuilder:validation:Optional
+	Exposure *Exposure `json:"exposure,omitempty"`
 }
 
 type GRPC struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 	Image     string                `json:"image,omitempty"`
+	// Specify the scheduling of the MLMD GRPC pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
 	// +kubebuilder:validation:Optional
 	Port string `json:"port"`
 }
@@ -387,9 +795,86 @@ type WorkflowController struct {
 	Deploy        bool   `json:"deploy"`
 	Image         string `json:"image,omitempty"`
 	ArgoExecImage string `json:"argoExecImage,omitempty"`
-	CustomConfig  string `json:"customConfig,omitempty"`
+	// Managed deploys a workflow controller for the DSPA when deploy is true. External runs the pipelines with the
+	// Argo Workflows installation of the cluster instead, whose compatibility with the DSP version is reported by the
+	// ExternalWorkflowControllerCompatible condition. Default: Managed
+	// +kubebuilder:default:=Managed
+	// +kubebuilder:validation:Optional
+	Mode WorkflowControllerMode `json:"mode,omitempty"`
+	// Assign an Argo instanceID to the workflow controller of the DSPA and label the pipeline runs with it, so the
+	// workflow controller only runs the workflows of the DSPA and the other workflow controllers of the cluster ignore
+	// them. The API server labels the runs it creates. The workflows of the recurring runs are labeled when the
+	// ScheduledWorkflow controller creates them by a webhook served by the operator, whose certificate is issued by the
+	// OpenShift service CA. The webhook does not block the creation of the workflows while the operator is unavailable,
+	// such workflows are not labeled and not run. Only applies to the Managed mode. The runs in progress when it is
+	// enabled or disabled are not picked up by the new workflow controller. Default: false
+	// +kubebuilder:validation:Optional
+	InstanceIDIsolation bool `json:"instanceIDIsolation,omitempty"`
+	// Name of a ConfigMap in the namespace of the DSPA deep-merged over the workflow controller configuration
+	// generated by the operator. Each key of the ConfigMap holds the YAML of a top-level setting of the Argo workflow
+	// controller configuration, e.g. artifactRepository or executor. The config fields take precedence.
+	CustomConfig string `json:"customConfig,omitempty"`
+	// Settings of the Argo workflow controller configuration.
+	// +kubebuilder:validation:Optional
+	Config *WorkflowControllerConfig `json:"config,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Specify the scheduling of this component's pods. Fields that are set replace the same field of spec.podPlacement.
+	// +kubebuilder:validation:Optional
+	PodPlacement *PodPlacement `json:"podPlacement,omitempty"`
+	// Extra env vars, volumes and sidecar containers added to this component's pods. Env vars, volumes, mount
+	// paths and containers managed by the operator cannot be overridden.
+	// +kubebuilder:validation:Optional
+	PodTemplateOverrides *PodTemplateOverrides `json:"podTemplateOverrides,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Managed;External
+type WorkflowControllerMode string
+
+const (
+	WorkflowControllerModeManaged  WorkflowControllerMode = "Managed"
+	WorkflowControllerModeExternal WorkflowControllerMode = "External"
+)
+
+// +kubebuilder:validation:Enum=OnPodCompletion;OnPodSuccess;OnWorkflowCompletion;OnWorkflowSuccess
+type PodGCStrategy string
+
+const (
+	PodGCOnPodCompletion      PodGCStrategy = "OnPodCompletion"
+	PodGCOnPodSuccess         PodGCStrategy = "OnPodSuccess"
+	PodGCOnWorkflowCompletion PodGCStrategy = "OnWorkflowCompletion"
+	PodGCOnWorkflowSuccess    PodGCStrategy = "OnWorkflowSuccess"
+)
+
+type WorkflowControllerConfig struct {
+	// Format of the artifact keys in the bucket, which can reference workflow variables, e.g.
+	// "artifacts/{{workflow.name}}/{{workflow.creationTimestamp.Y}}/{{pod.name}}". Default: {{workflow.name}}/{{pod.name}}
+	// +kubebuilder:validation:Optional
+	KeyFormat string `json:"keyFormat,omitempty"`
+	// Archive the logs of the pipeline steps in the artifact repository. Default: false
+	// +kubebuilder:validation:Optional
+	ArchiveLogs *bool `json:"archiveLogs,omitempty"`
+	// Maximum number of workflows running at the same time. Default: unlimited
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// Maximum number of workflows running at the same time in the namespace. Default: unlimited
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	NamespaceParallelism *int32 `json:"namespaceParallelism,omitempty"`
+	// When the pods of the workflows are deleted. Default: the pods are not deleted
+	// +kubebuilder:validation:Optional
+	PodGCStrategy PodGCStrategy `json:"podGCStrategy,omitempty"`
+	// Default Argo Workflow, with metadata and spec, merged into every workflow. The podGCStrategy takes precedence
+	// over its spec.podGC.strategy.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	WorkflowDefaults *runtime.RawExtension `json:"workflowDefaults,omitempty"`
+	// Resource requirements of the executor containers of the pipeline steps.
+	// +kubebuilder:validation:Optional
+	ExecutorResources *ResourceRequirements `json:"executorResources,omitempty"`
 }
 
 // ResourceRequirements structures compute resource requirements.
@@ -405,6 +890,161 @@ type Resources struct {
 	Memory resource.Quantity `json:"memory,omitempty"`
 }
 
+// PodPlacement constrains the nodes the pods of a component are scheduled on and how they are spread across them.
+type PodPlacement struct {
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// The affinity schema is not embedded in the CRD to keep it within the size limits, it is validated when the
+	// component's Deployment is applied.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Constraints without a labelSelector spread the pods of the component they are applied to.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Auto;Route;Ingress;HTTPRoute;None
+type ExposureType string
+
+const (
+	ExposureAuto      ExposureType = "Auto"
+	ExposureRoute     ExposureType = "Route"
+	ExposureIngress   ExposureType = "Ingress"
+	ExposureHTTPRoute ExposureType = "HTTPRoute"
+	ExposureNone      ExposureType = "None"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type != 'Ingress' || has(self.ingress)",message="ingress must be specified for the Ingress type"
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type != 'HTTPRoute' || has(self.httpRoute)",message="httpRoute must be specified for the HTTPRoute type"
+type Exposure struct {
+	// Auto selects, based on the APIs the cluster serves, an HTTPRoute when httpRoute is set and the Gateway API is
+	// installed, otherwise an Ingress when ingress is set, otherwise a Route on OpenShift. None removes the exposure.
+	// Default: Auto
+	// +kubebuilder:default:=Auto
+	// +kubebuilder:validation:Optional
+	Type ExposureType `json:"type,omitempty"`
+	// Options of the OpenShift Route, used when the component is exposed with a Route.
+	// +kubebuilder:validation:Optional
+	Route *RouteExposure `json:"route,omitempty"`
+	// +kubebuilder:validation:Optional
+	Ingress *IngressExposure `json:"ingress,omitempty"`
+	// +kubebuilder:validation:Optional
+	HTTPRoute *HTTPRouteExposure `json:"httpRoute,omitempty"`
+}
+
+type RouteExposure struct {
+	// Host of the Route. Defaults to the host generated by the cluster, e.g. ds-pipeline-<name>-<namespace>.apps...
+	// +kubebuilder:validation:Optional
+	Host string `json:"host,omitempty"`
+	// +kubebuilder:validation:Optional
+	TLS *RouteTLS `json:"tls,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Edge;Reencrypt;Passthrough
+type RouteTLSTermination string
+
+const (
+	RouteTLSTerminationEdge        RouteTLSTermination = "Edge"
+	RouteTLSTerminationReencrypt   RouteTLSTermination = "Reencrypt"
+	RouteTLSTerminationPassthrough RouteTLSTermination = "Passthrough"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.termination) || self.termination != 'Passthrough' || !has(self.certificateSecretName)",message="certificateSecretName cannot be set with the Passthrough termination"
+type RouteTLS struct {
+	// Termination of the TLS connections by the router. Defaults to Reencrypt for the DSP API Server and the MLMD
+	// Envoy, which serve HTTPS, and to Edge for Minio, which serves plain HTTP and only supports Edge.
+	// +kubebuilder:validation:Optional
+	Termination RouteTLSTermination `json:"termination,omitempty"`
+	// Secret of type kubernetes.io/tls holding the certificate (tls.crt) and key (tls.key) served by the router for
+	// the host of the Route, and optionally the CA certificate of its chain (ca.crt). Defaults to the certificate of
+	// the cluster ingress. The Route references the Secret through spec.tls.externalCertificate, which requires an
+	// OpenShift version with the RouteExternalCertificate feature, and the router is granted read access to it. The
+	// key is not copied to the Route.
+	// +kubebuilder:validation:Optional
+	CertificateSecretName string `json:"certificateSecretName,omitempty"`
+}
+
+type IngressExposure struct {
+	// +kubebuilder:validation:Optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+	// Secret holding the TLS certificate of the host. The Ingress serves plain HTTP without it.
+	// +kubebuilder:validation:Optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// Annotations of the Ingress, e.g. to configure the ingress controller. Note that the DSP API Server and the MLMD
+	// Envoy serve HTTPS, which most ingress controllers must be told through an annotation.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type HTTPRouteExposure struct {
+	// Gateway the HTTPRoute is attached to.
+	// +kubebuilder:validation:Required
+	ParentRef GatewayParentReference `json:"parentRef"`
+	// Hostname matched by the HTTPRoute, reported as https://<hostname> in the component status. Note that the DSP API
+	// Server and the MLMD Envoy serve HTTPS, which requires a BackendTLSPolicy on most Gateway implementations.
+	// +kubebuilder:validation:Optional
+	Hostname string `json:"hostname,omitempty"`
+}
+
+type GatewayParentReference struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Defaults to the namespace of the DSPA.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// PodTemplateOverrides are added to the pod template of a component's Deployment. Env, envFrom and volumeMounts apply
+// to the main container of the component. The schemas of the lists are not embedded in the CRD to keep it within the
+// size limits, they are validated when the DSPA is reconciled.
+type PodTemplateOverrides struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Sidecar containers, added after the containers of the component.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Containers []corev1.Container `json:"containers,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="(has(self.provider) && self.provider != 's3') || (has(self.credentialsMode) && self.credentialsMode == 'WebIdentity' ? has(self.webIdentity) : has(self.s3CredentialsSecret))",message="s3CredentialsSecret must be specified for the Static credentialsMode, webIdentity for the WebIdentity credentialsMode"
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider != 'gcs' || has(self.gcsCredentialsSecret)",message="gcsCredentialsSecret must be specified for the gcs provider"
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider != 'azure' || has(self.azureCredentialsSecret)",message="azureCredentialsSecret must be specified for the azure provider"
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider == 's3' || !has(self.credentialsMode) || self.credentialsMode == 'Static'",message="the WebIdentity credentialsMode is only supported by the s3 provider"
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider == 's3' || !has(self.manageBucket)",message="manageBucket is only supported by the s3 provider"
+// +kubebuilder:validation:XValidation:rule="!has(self.manageBucket) || !has(self.manageBucket.artifactExpirationDays) || (has(self.basePath) && self.basePath.matches('[^/]'))",message="manageBucket.artifactExpirationDays requires a basePath"
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider == 's3' || !has(self.encryption)",message="encryption is only supported by the s3 provider"
 type ExternalStorage struct {
 	// +kubebuilder:validation:Required
 	Host   string `json:"host"`
@@ -414,12 +1054,109 @@ type ExternalStorage struct {
 	Region string `json:"region"`
 	// Subpath where objects should be stored for this DSPA
 	// +kubebuilder:validation:Optional
-	BasePath            string `json:"basePath"`
-	*S3CredentialSecret `json:"s3CredentialsSecret"`
+	BasePath string `json:"basePath"`
+	// Required when credentialsMode is Static.
+	// +kubebuilder:validation:Optional
+	*S3CredentialSecret `json:"s3CredentialsSecret,omitempty"`
 	// +kubebuilder:validation:Optional
 	Secure *bool `json:"secure"`
 	// +kubebuilder:validation:Optional
 	Port string `json:"port"`
+	// How DSP components authenticate to the object store. Static uses the access and secret keys from
+	// s3CredentialsSecret. WebIdentity exchanges a projected service account token for temporary credentials
+	// of the IAM role in webIdentity (e.g. IRSA), no long-lived keys are used. Default: Static
+	// +kubebuilder:validation:Enum=Static;WebIdentity
+	// +kubebuilder:default:=Static
+	// +kubebuilder:validation:Optional
+	CredentialsMode string `json:"credentialsMode,omitempty"`
+	// Required when credentialsMode is WebIdentity.
+	// +kubebuilder:validation:Optional
+	WebIdentity *WebIdentity `json:"webIdentity,omitempty"`
+	// The object storage API. s3 covers any S3 compatible store. gcs uses Google Cloud Storage with the host set
+	// to storage.googleapis.com, azure uses Azure Blob Storage with the host set to <account>.blob.core.windows.net
+	// and the bucket being the blob container. Artifact downloads through the API Server are only supported
+	// with s3. Default: s3
+	// +kubebuilder:validation:Enum=s3;gcs;azure
+	// +kubebuilder:default:=s3
+	// +kubebuilder:validation:Optional
+	Provider string `json:"provider,omitempty"`
+	// Required when provider is gcs.
+	// +kubebuilder:validation:Optional
+	GCSCredentialSecret *GCSCredentialSecret `json:"gcsCredentialsSecret,omitempty"`
+	// Required when provider is azure.
+	// +kubebuilder:validation:Optional
+	AzureCredentialSecret *AzureCredentialSecret `json:"azureCredentialsSecret,omitempty"`
+	// Let the operator manage the bucket. The operator creates the bucket and keeps its versioning, artifact
+	// expiration lifecycle rule and default encryption in sync, so the credentials need the matching bucket
+	// permissions. Only supported
+	// with the s3 provider.
+	// +kubebuilder:validation:Optional
+	ManageBucket *ManageBucket `json:"manageBucket,omitempty"`
+	// Server-side encryption of the objects written to the bucket. The Argo Workflows artifact repository, which holds
+	// the step logs and the artifacts Argo writes, requests it explicitly. The KFP API server and launcher have no
+	// server-side encryption settings, so the pipeline artifacts they write are encrypted by the default encryption
+	// of the bucket: it is set to match when manageBucket is set, and a mismatch is reported by the
+	// BucketEncryptionInSync condition otherwise. Only supported with the s3 provider.
+	// +kubebuilder:validation:Optional
+	Encryption *ObjectStorageEncryption `json:"encryption,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.type != 'SSE-KMS' || has(self.kmsKeyId)",message="kmsKeyId must be specified for SSE-KMS"
+type ObjectStorageEncryption struct {
+	// SSE-S3 encrypts with keys managed by the object store, SSE-KMS with the KMS key kmsKeyId. Customer provided
+	// keys (SSE-C) are not supported, the API server could not read the step logs archived by Argo Workflows. A
+	// misconfigured key is only detected by the ReadWrite health check.
+	// +kubebuilder:validation:Enum=SSE-S3;SSE-KMS
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+	// The ID or ARN of the KMS key. Required for SSE-KMS.
+	// +kubebuilder:validation:Optional
+	KMSKeyID string `json:"kmsKeyId,omitempty"`
+}
+
+type ManageBucket struct {
+	// Create the bucket if it does not exist. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	CreateIfMissing bool `json:"createIfMissing,omitempty"`
+	// Expire the artifacts under the basePath after this number of days with a lifecycle rule owned by the operator.
+	// Requires a basePath that does not contain the MariaDB backups, since every object under it expires. The
+	// lifecycle rule is removed when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	ArtifactExpirationDays *int32 `json:"artifactExpirationDays,omitempty"`
+	// The versioning state of the bucket, left unchanged when unset. Noncurrent artifact versions also expire after
+	// artifactExpirationDays when versioning is Enabled.
+	// +kubebuilder:validation:Enum=Enabled;Suspended
+	// +kubebuilder:validation:Optional
+	Versioning string `json:"versioning,omitempty"`
+}
+
+type GCSCredentialSecret struct {
+	// +kubebuilder:validation:Required
+	// The name of the Secret where the service account key is defined.
+	SecretName string `json:"secretName"`
+	// The "Key" in the k8sSecret key/value pairs holding the JSON service account key.
+	ServiceAccountKey string `json:"serviceAccountKey"`
+}
+
+type AzureCredentialSecret struct {
+	// +kubebuilder:validation:Required
+	// The name of the Secret where the storage account key is defined.
+	SecretName string `json:"secretName"`
+	// The "Key" in the k8sSecret key/value pairs holding the storage account key.
+	AccountKey string `json:"accountKey"`
+}
+
+type WebIdentity struct {
+	// The ARN of the IAM role assumed with the service account token.
+	// +kubebuilder:validation:Required
+	RoleARN string `json:"roleArn"`
+	// The audience of the projected service account token, which must be trusted by the role's identity provider.
+	// Default: sts.amazonaws.com
+	// +kubebuilder:default:=sts.amazonaws.com
+	// +kubebuilder:validation:Optional
+	Audience string `json:"audience,omitempty"`
 }
 
 type S3CredentialSecret struct {
@@ -457,8 +1194,71 @@ type ProxyConfig struct {
 
 type DSPAStatus struct {
 	// +kubebuilder:validation:Optional
-	Components ComponentStatus    `json:"components,omitempty"`
-	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Components ComponentStatus `json:"components,omitempty"`
+	// +kubebuilder:validation:Optional
+	DatabaseBackup *DatabaseBackupStatus `json:"databaseBackup,omitempty"`
+	// +kubebuilder:validation:Optional
+	CredentialRotation *CredentialRotationStatus `json:"credentialRotation,omitempty"`
+	// +kubebuilder:validation:Optional
+	ObjectStorage *ObjectStorageStatus `json:"objectStorage,omitempty"`
+	// +kubebuilder:validation:Optional
+	CommonMetadata *CommonMetadataStatus `json:"commonMetadata,omitempty"`
+	Conditions     []metav1.Condition    `json:"conditions,omitempty"`
+}
+
+// CommonMetadataStatus records the keys of spec.commonLabels, spec.commonAnnotations, spec.podLabels and
+// spec.podAnnotations applied to the DSPA resources. Keys removed from the spec stay listed until they have been
+// removed from all the resources.
+type CommonMetadataStatus struct {
+	// +kubebuilder:validation:Optional
+	Labels []string `json:"labels,omitempty"`
+	// +kubebuilder:validation:Optional
+	Annotations []string `json:"annotations,omitempty"`
+	// +kubebuilder:validation:Optional
+	PodLabels []string `json:"podLabels,omitempty"`
+	// +kubebuilder:validation:Optional
+	PodAnnotations []string `json:"podAnnotations,omitempty"`
+}
+
+type ObjectStorageStatus struct {
+	// Availability of the additionalStores, verified by the object storage health check.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	AdditionalStores []AdditionalObjectStoreStatus `json:"additionalStores,omitempty"`
+}
+
+type AdditionalObjectStoreStatus struct {
+	Name string `json:"name"`
+	// Whether the store was successfully reached with its credentials.
+	Available bool `json:"available"`
+	// Why the store is not available.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+type CredentialRotationStatus struct {
+	// Most recent credential rotations, newest first.
+	// +kubebuilder:validation:Optional
+	History []CredentialRotationRecord `json:"history,omitempty"`
+}
+
+type CredentialRotationRecord struct {
+	// Component whose credentials were rotated, either Database or ObjectStorage.
+	Component string `json:"component"`
+	// Name of the Secret holding the rotated credentials.
+	SecretName string `json:"secretName"`
+	// Time at which the credentials were rotated.
+	RotationTime metav1.Time `json:"rotationTime"`
+}
+
+type DatabaseBackupStatus struct {
+	// Time at which the most recent database backup completed successfully.
+	// +kubebuilder:validation:Optional
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+	// Time at which the most recent database backup was scheduled.
+	// +kubebuilder:validation:Optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
 }
 
 type ComponentStatus struct {
@@ -25,7 +25,8 @@ import (
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
-	v1 "github.com/openshift/api/route/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -34,11 +35,24 @@ var apiServerTemplatesDir = "apiserver/default"
 const (
 	apiServerDefaultResourceNamePrefix = "ds-pipeline-"
 	apiServerServerConfigTemplate      = "apiserver/default/server-config.yaml.tmpl"
+	apiServerServiceAccountTemplate    = "apiserver/default/sa_ds-pipeline.yaml.tmpl"
 )
 
-// serverRoute is a resource deployed conditionally
-// as such it is handled separately
-const serverRoute = "apiserver/route/route.yaml.tmpl"
+// The resources exposing the API Server are deployed conditionally
+// as such they are handled separately
+var apiServerExposureTemplates = exposureTemplates{
+	route:                "apiserver/route/route.yaml.tmpl",
+	ingress:              "apiserver/route/ingress.yaml.tmpl",
+	httpRoute:            "apiserver/route/httproute.yaml.tmpl",
+	routeTLSTerminations: []dspav1.RouteTLSTermination{dspav1.RouteTLSTerminationReencrypt, dspav1.RouteTLSTerminationPassthrough},
+}
+
+// The PodDisruptionBudget and HorizontalPodAutoscaler of the API Server are
+// deployed conditionally, depending on its high availability configuration
+const (
+	apiServerPodDisruptionBudget     = "apiserver/high-availability/poddisruptionbudget.yaml.tmpl"
+	apiServerHorizontalPodAutoscaler = "apiserver/high-availability/horizontalpodautoscaler.yaml.tmpl"
+)
 
 // Sample Pipeline and Config are resources deployed conditionally
 // as such it is handled separately
@@ -175,7 +189,20 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1.Dat
 	// Config hash for pod rollout when sample config, workspace, plugins, managed pipelines, or platform version change.
 	params.APIServerConfigHash = fmt.Sprintf("%x", sha256.Sum256([]byte(combinedConfigHashInput)))
 
+	params.APIServerExposure, err = r.resolveExposure(dsp.Spec.APIServer.Exposure, dsp.Spec.APIServer.EnableRoute)
+	if err != nil {
+		return err
+	}
+	// The API Server is exposed through the kube-rbac-proxy deployed with enableOauth
+	if params.APIServerExposure.Type != dspav1.ExposureNone {
+		params.APIServer.EnableRoute = true
+	}
+
 	log.Info("Applying APIServer Resources")
+	err = r.reconcileCertificate(ctx, dsp, params, apiServerCertificate, apiServerCertificateSecretNamePrefix+dsp.Name, true)
+	if err != nil {
+		return err
+	}
 	if err := r.Apply(dsp, params, apiServerServerConfigTemplate); err != nil {
 		return err
 	}
@@ -194,15 +221,33 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1.Dat
 		return err
 	}
 
-	if dsp.Spec.APIServer.EnableRoute {
-		err := r.Apply(dsp, params, serverRoute)
+	exposureTemplates := apiServerExposureTemplates
+	exposureTemplates.name = params.APIServerDefaultResourceName
+	err = r.reconcileExposure(ctx, dsp, params, &params.APIServerExposure, exposureTemplates)
+	if err != nil {
+		return err
+	}
+
+	apiServerNamespacedName := types.NamespacedName{Name: params.APIServerDefaultResourceName, Namespace: dsp.Namespace}
+	if params.APIServerHighlyAvailable {
+		err := r.Apply(dsp, params, apiServerPodDisruptionBudget)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := r.DeleteResourceIfItExists(ctx, &policyv1.PodDisruptionBudget{}, apiServerNamespacedName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if params.APIServerAutoscaling != nil {
+		err := r.Apply(dsp, params, apiServerHorizontalPodAutoscaler)
 		if err != nil {
 			return err
 		}
 	} else {
-		route := &v1.Route{}
-		namespacedNamed := types.NamespacedName{Name: "ds-pipeline-" + dsp.Name, Namespace: dsp.Namespace}
-		err := r.DeleteResourceIfItExists(ctx, route, namespacedNamed)
+		err := r.DeleteResourceIfItExists(ctx, &autoscalingv2.HorizontalPodAutoscaler{}, apiServerNamespacedName)
 		if err != nil {
 			return err
 		}
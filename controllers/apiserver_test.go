@@ -27,13 +27,16 @@ import (
 
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
 
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -1332,3 +1335,177 @@ func TestManagedPipelineSampleEntry_VersionNameIsPlatformVersionOnly(t *testing.
 			"managed pipeline versionName should be the platform version only")
 	})
 }
+
+func TestSetupAPIServerHighAvailability(t *testing.T) {
+	replicas := int32(3)
+	tests := []struct {
+		name                string
+		apiServer           *dspav1.APIServer
+		expectedReplicas    int32
+		expectedHA          bool
+		expectedAutoscaling *dspav1.APIServerAutoscaling
+	}{
+		{
+			name:             "no API Server",
+			expectedReplicas: 1,
+		},
+		{
+			name:             "single replica by default",
+			apiServer:        &dspav1.APIServer{Deploy: true},
+			expectedReplicas: 1,
+		},
+		{
+			name:             "explicit replicas",
+			apiServer:        &dspav1.APIServer{Deploy: true, Replicas: &replicas},
+			expectedReplicas: 3,
+			expectedHA:       true,
+		},
+		{
+			name: "high availability preset runs at least two replicas",
+			apiServer: &dspav1.APIServer{Deploy: true,
+				HighAvailability: &dspav1.APIServerHighAvailability{Enabled: true}},
+			expectedReplicas: 2,
+			expectedHA:       true,
+		},
+		{
+			name: "high availability preset keeps more replicas",
+			apiServer: &dspav1.APIServer{Deploy: true, Replicas: &replicas,
+				HighAvailability: &dspav1.APIServerHighAvailability{Enabled: true}},
+			expectedReplicas: 3,
+			expectedHA:       true,
+		},
+		{
+			name: "autoscaling hands replicas over to the HorizontalPodAutoscaler",
+			apiServer: &dspav1.APIServer{Deploy: true, Replicas: &replicas,
+				HighAvailability: &dspav1.APIServerHighAvailability{
+					Enabled:     true,
+					Autoscaling: &dspav1.APIServerAutoscaling{MaxReplicas: 5},
+				}},
+			expectedReplicas: 0,
+			expectedHA:       true,
+			expectedAutoscaling: &dspav1.APIServerAutoscaling{
+				MinReplicas:                    util.Int32Pointer(2),
+				MaxReplicas:                    5,
+				TargetCPUUtilizationPercentage: util.Int32Pointer(80),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := &DSPAParams{APIServer: tt.apiServer}
+			params.SetupAPIServerHighAvailability()
+			assert.Equal(t, tt.expectedReplicas, params.APIServerReplicas)
+			assert.Equal(t, tt.expectedHA, params.APIServerHighlyAvailable)
+			assert.Equal(t, tt.expectedAutoscaling, params.APIServerAutoscaling)
+		})
+	}
+}
+
+func TestDeployAPIServerHighAvailability(t *testing.T) {
+	testNamespace := "testnamespace"
+	expectedAPIServerName := "ds-pipeline-testdspa"
+
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	dspa.Spec.APIServer.HighAvailability = &dspav1.APIServerHighAvailability{
+		Enabled:     true,
+		Autoscaling: &dspav1.APIServerAutoscaling{MaxReplicas: 4},
+	}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	// Replicas are left to the HorizontalPodAutoscaler
+	assert.Nil(t, deployment.Spec.Replicas)
+	affinity := deployment.Spec.Template.Spec.Affinity
+	require.NotNil(t, affinity)
+	require.NotNil(t, affinity.PodAntiAffinity)
+	require.Len(t, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	term := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	assert.Equal(t, corev1.LabelHostname, term.TopologyKey)
+	assert.Equal(t, map[string]string{"app": expectedAPIServerName}, term.LabelSelector.MatchLabels)
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	created, err = reconciler.IsResourceCreated(ctx, pdb, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	created, err = reconciler.IsResourceCreated(ctx, hpa, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(4), hpa.Spec.MaxReplicas)
+	assert.Equal(t, expectedAPIServerName, hpa.Spec.ScaleTargetRef.Name)
+	require.Len(t, hpa.Spec.Metrics, 1)
+	assert.Equal(t, int32(80), *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+
+	// Replicas still starting after a scale up by the HorizontalPodAutoscaler do not affect readiness
+	hpaReplicas := int32(4)
+	deployment.Spec.Replicas = &hpaReplicas
+	require.NoError(t, reconciler.Client.Update(ctx, deployment))
+	deployment.Status.AvailableReplicas = 2
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+	require.NoError(t, reconciler.Client.Status().Update(ctx, deployment))
+	apiServerReady, err := reconciler.evaluateCondition(ctx, dspa, expectedAPIServerName, config.APIServerReady)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionTrue, apiServerReady.Status)
+	assert.Contains(t, apiServerReady.Message, "2 of 4 desired replicas")
+
+	// Fixed replicas remove the HorizontalPodAutoscaler
+	replicas := int32(3)
+	dspa.Spec.APIServer.Replicas = &replicas
+	dspa.Spec.APIServer.HighAvailability = nil
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.NotNil(t, deployment.Spec.Replicas)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	created, err = reconciler.IsResourceCreated(ctx, &autoscalingv2.HorizontalPodAutoscaler{}, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	assert.False(t, created)
+	created, err = reconciler.IsResourceCreated(ctx, &policyv1.PodDisruptionBudget{}, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// Readiness requires the replicas guaranteed by the PodDisruptionBudget
+	deployment.Status.AvailableReplicas = 1
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+	require.NoError(t, reconciler.Client.Status().Update(ctx, deployment))
+	apiServerReady, err = reconciler.evaluateCondition(ctx, dspa, expectedAPIServerName, config.APIServerReady)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionFalse, apiServerReady.Status)
+	assert.Equal(t, config.DesiredReplicasUnavailable, apiServerReady.Reason)
+
+	// A replica down during a rolling update or node drain only reports the shortfall
+	deployment.Status.AvailableReplicas = 2
+	require.NoError(t, reconciler.Client.Status().Update(ctx, deployment))
+	apiServerReady, err = reconciler.evaluateCondition(ctx, dspa, expectedAPIServerName, config.APIServerReady)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionTrue, apiServerReady.Status)
+	assert.Contains(t, apiServerReady.Message, "2 of 3 desired replicas")
+
+	deployment.Status.AvailableReplicas = 3
+	require.NoError(t, reconciler.Client.Status().Update(ctx, deployment))
+	apiServerReady, err = reconciler.evaluateCondition(ctx, dspa, expectedAPIServerName, config.APIServerReady)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionTrue, apiServerReady.Status)
+
+	// A single replica removes the PodDisruptionBudget
+	dspa.Spec.APIServer.Replicas = nil
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &policyv1.PodDisruptionBudget{}, expectedAPIServerName, testNamespace)
+	require.NoError(t, err)
+	assert.False(t, created)
+}
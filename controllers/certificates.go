@@ -0,0 +1,128 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The Certificates are named after the Secrets the service-ca operator generates for the same Services, so the
+// components mount the same Secrets with either certificate provider.
+const (
+	apiServerCertificate                 = "cert-manager/apiserver.certificate.yaml.tmpl"
+	apiServerCertificateSecretNamePrefix = "ds-pipelines-proxy-tls-"
+	mlmdEnvoyCertificate                 = "cert-manager/metadata-envoy.certificate.yaml.tmpl"
+	mlmdEnvoyCertificateSecretNamePrefix = "ds-pipelines-envoy-proxy-tls-"
+	mlmdGrpcCertificate                  = "cert-manager/metadata-grpc.certificate.yaml.tmpl"
+	mlmdGrpcCertificateSecretNamePrefix  = "ds-pipeline-metadata-grpc-tls-certs-"
+	mariaDBCertificate                   = "cert-manager/mariadb.certificate.yaml.tmpl"
+	mariaDBCertificateSecretNamePrefix   = "ds-pipelines-mariadb-tls-"
+
+	certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+	certManagerDefaultIssuerKind         = "Issuer"
+	certManagerDefaultIssuerGroup        = "cert-manager.io"
+)
+
+// SetupCertificateProvider sets the cert-manager configuration when the certificates are issued by cert-manager
+func (p *DSPAParams) SetupCertificateProvider(dsp *dspav1.DataSciencePipelinesApplication) error {
+	p.CertManager = nil
+	if dsp.Spec.TLS == nil || dsp.Spec.TLS.CertificateProvider != dspav1.CertificateProviderCertManager {
+		return nil
+	}
+	if dsp.Spec.TLS.CertManager == nil {
+		return errors.New("tls.certManager must be specified for the CertManager certificateProvider")
+	}
+	p.CertManager = dsp.Spec.TLS.CertManager.DeepCopy()
+	setStringDefault(certManagerDefaultIssuerKind, &p.CertManager.IssuerRef.Kind)
+	setStringDefault(certManagerDefaultIssuerGroup, &p.CertManager.IssuerRef.Group)
+	return nil
+}
+
+// LoadCertManagerCA returns the CA of the certificates issued by cert-manager, found in the Secret of the API Server
+// certificate. It is empty until the certificate is issued.
+func (p *DSPAParams) LoadCertManagerCA(ctx context.Context, client client.Client) (string, error) {
+	secret, err := util.GetSecret(ctx, apiServerCertificateSecretNamePrefix+p.Name, p.Namespace, client)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(secret.Data["ca.crt"]), nil
+}
+
+// reconcileCertificate applies the cert-manager Certificate of a component when its certificates are issued by
+// cert-manager, and removes it otherwise
+func (r *DSPAReconciler) reconcileCertificate(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams, template, name string, required bool) error {
+
+	if params.CertManager != nil && required {
+		if !r.ClusterCapabilities.Certificates {
+			return errors.New("tls.certificateProvider CertManager requires cert-manager, which is not installed on the cluster")
+		}
+		return r.Apply(dsp, params, template)
+	}
+	if !r.ClusterCapabilities.Certificates {
+		return nil
+	}
+
+	namespacedName := types.NamespacedName{Name: name, Namespace: dsp.Namespace}
+	certificate := newCertificate()
+	err := r.Get(ctx, namespacedName, certificate)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := r.Delete(ctx, certificate); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	// The Secret is left behind by cert-manager, remove it so the service-ca operator can generate its own
+	return r.DeleteResourceIfItExists(ctx, &corev1.Secret{}, namespacedName)
+}
+
+// certManagerSecretReconcileRequests returns the DSPA of a Secret issued by cert-manager for one of its Certificates,
+// which must be reconciled when the certificate is renewed
+func certManagerSecretReconcileRequests(secret *corev1.Secret) []reconcile.Request {
+	if secret.Annotations[certManagerCertificateNameAnnotation] == "" {
+		return nil
+	}
+	dspaName, hasDSPALabel := secret.Labels["dspa"]
+	if !hasDSPALabel || !util.HasSupportedDSPVersionLabel(secret.Labels) {
+		return nil
+	}
+	namespacedName := types.NamespacedName{Name: dspaName, Namespace: secret.Namespace}
+	return []reconcile.Request{{NamespacedName: namespacedName}}
+}
+
+// newCertificate returns an empty cert-manager Certificate. The cert-manager types are not part of the operator
+// scheme since cert-manager is optional.
+func newCertificate() *unstructured.Unstructured {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	return certificate
+}
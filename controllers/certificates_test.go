@@ -0,0 +1,144 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func certManagerDSPA() *dspav1.DataSciencePipelinesApplication {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.TLS = &dspav1.TLSConfig{
+		CertificateProvider: dspav1.CertificateProviderCertManager,
+		CertManager: &dspav1.CertManagerConfig{
+			IssuerRef: dspav1.CertManagerIssuerReference{Name: "dspa-ca-issuer"},
+		},
+	}
+	return dspa
+}
+
+func TestSetupCertificateProvider(t *testing.T) {
+	params := &DSPAParams{}
+	dspa := certManagerDSPA()
+	require.NoError(t, params.SetupCertificateProvider(dspa))
+	require.NotNil(t, params.CertManager)
+	assert.Equal(t, dspav1.CertManagerIssuerReference{
+		Name:  "dspa-ca-issuer",
+		Kind:  "Issuer",
+		Group: "cert-manager.io",
+	}, params.CertManager.IssuerRef)
+
+	dspa.Spec.TLS.CertManager = nil
+	assert.EqualError(t, params.SetupCertificateProvider(dspa), "tls.certManager must be specified for the CertManager certificateProvider")
+
+	dspa.Spec.TLS = &dspav1.TLSConfig{CertificateProvider: dspav1.CertificateProviderServiceCA}
+	require.NoError(t, params.SetupCertificateProvider(dspa))
+	assert.Nil(t, params.CertManager)
+}
+
+func TestDeployAPIServerCertManagerCertificate(t *testing.T) {
+	dspa := certManagerDSPA()
+	ctx, params, reconciler := CreateNewTestObjects()
+	reconciler.ClusterCapabilities.Certificates = true
+
+	// The CA of the issuer is trusted once the first certificate is issued
+	certificateSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipelines-proxy-tls-testdspa", Namespace: "testnamespace"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("cert"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			"ca.crt":                []byte("-----BEGIN CERTIFICATE-----\nissuer-ca\n-----END CERTIFICATE-----\n"),
+		},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, certificateSecret))
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Contains(t, params.APICustomPemCerts, certificateSecret.Data["ca.crt"])
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	certificate := newCertificate()
+	namespacedName := types.NamespacedName{Name: "ds-pipelines-proxy-tls-testdspa", Namespace: "testnamespace"}
+	require.NoError(t, reconciler.Client.Get(ctx, namespacedName, certificate))
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	assert.Equal(t, "ds-pipelines-proxy-tls-testdspa", secretName)
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	assert.Contains(t, dnsNames, "ds-pipeline-testdspa.testnamespace.svc.cluster.local")
+	issuerRef, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef")
+	assert.Equal(t, map[string]string{"name": "dspa-ca-issuer", "kind": "Issuer", "group": "cert-manager.io"}, issuerRef)
+
+	// The service-ca operator does not issue the certificate
+	service := &corev1.Service{}
+	created, err := reconciler.IsResourceCreated(ctx, service, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.NotContains(t, service.Annotations, "service.beta.openshift.io/serving-cert-secret-name")
+
+	// Switching back to the service-ca operator removes the Certificate and its Secret
+	dspa.Spec.TLS = nil
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	created, err = reconciler.IsResourceCreated(ctx, newCertificate(), "ds-pipelines-proxy-tls-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+	created, err = reconciler.IsResourceCreated(ctx, &corev1.Secret{}, "ds-pipelines-proxy-tls-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+	service = &corev1.Service{}
+	created, err = reconciler.IsResourceCreated(ctx, service, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, "ds-pipelines-proxy-tls-testdspa", service.Annotations["service.beta.openshift.io/serving-cert-secret-name"])
+}
+
+func TestCertManagerRequiresCertManager(t *testing.T) {
+	dspa := certManagerDSPA()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	err := reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.EqualError(t, err, "tls.certificateProvider CertManager requires cert-manager, which is not installed on the cluster")
+}
+
+func TestCertManagerSecretReconcileRequests(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ds-pipelines-proxy-tls-testdspa",
+			Namespace:   "testnamespace",
+			Annotations: map[string]string{"cert-manager.io/certificate-name": "ds-pipelines-proxy-tls-testdspa"},
+			Labels:      map[string]string{"dspa": "testdspa", "dsp-version": "v2"},
+		},
+	}
+	requests := certManagerSecretReconcileRequests(secret)
+	require.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Name: "testdspa", Namespace: "testnamespace"}, requests[0].NamespacedName)
+
+	// Secrets issued for other Certificates are ignored
+	delete(secret.Labels, "dspa")
+	assert.Empty(t, certManagerSecretReconcileRequests(secret))
+}
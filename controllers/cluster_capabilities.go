@@ -0,0 +1,76 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+var (
+	RouteGVK       = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+	IngressGVK     = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+	HTTPRouteGVK   = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+)
+
+// ClusterCapabilities are the optional APIs served by the cluster, detected when the operator starts
+type ClusterCapabilities struct {
+	// Routes is set on OpenShift
+	Routes bool
+	// Ingresses is set on clusters serving networking.k8s.io/v1 Ingresses
+	Ingresses bool
+	// HTTPRoutes is set on clusters with the Gateway API installed
+	HTTPRoutes bool
+	// Certificates is set on clusters with cert-manager installed
+	Certificates bool
+}
+
+// DetectClusterCapabilities discovers the APIs the cluster serves
+func DetectClusterCapabilities(discoveryClient discovery.DiscoveryInterface) (ClusterCapabilities, error) {
+	var capabilities ClusterCapabilities
+	var err error
+	if capabilities.Routes, err = servesKind(discoveryClient, RouteGVK); err != nil {
+		return ClusterCapabilities{}, err
+	}
+	if capabilities.Ingresses, err = servesKind(discoveryClient, IngressGVK); err != nil {
+		return ClusterCapabilities{}, err
+	}
+	if capabilities.HTTPRoutes, err = servesKind(discoveryClient, HTTPRouteGVK); err != nil {
+		return ClusterCapabilities{}, err
+	}
+	if capabilities.Certificates, err = servesKind(discoveryClient, CertificateGVK); err != nil {
+		return ClusterCapabilities{}, err
+	}
+	return capabilities, nil
+}
+
+func servesKind(discoveryClient discovery.DiscoveryInterface, gvk schema.GroupVersionKind) (bool, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == gvk.Kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -16,6 +16,8 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 )
 
@@ -23,7 +25,7 @@ var commonTemplatesDir = "common/default"
 
 const commonCusterRolebindingTemplate = "common/no-owner/clusterrolebinding.yaml.tmpl"
 
-func (r *DSPAReconciler) ReconcileCommon(dsp *dspav1.DataSciencePipelinesApplication, params *DSPAParams) error {
+func (r *DSPAReconciler) ReconcileCommon(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication, params *DSPAParams) error {
 	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
 
 	log.Info("Applying Common Resources")
@@ -35,6 +37,14 @@ func (r *DSPAReconciler) ReconcileCommon(dsp *dspav1.DataSciencePipelinesApplica
 	if err != nil {
 		return err
 	}
+	err = r.reconcileEgressNetworkPolicy(ctx, dsp, params)
+	if err != nil {
+		return err
+	}
+	err = r.reconcileRouteCertificateReader(ctx, dsp, params)
+	if err != nil {
+		return err
+	}
 
 	log.Info("Finished applying Common Resources")
 	return nil
@@ -0,0 +1,69 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+)
+
+// SetupCommonMetadata sets the labels and annotations added to every resource of the DSPA
+func (p *DSPAParams) SetupCommonMetadata(dsp *dspav1.DataSciencePipelinesApplication) {
+	p.CommonLabels = dsp.Spec.CommonLabels
+	p.CommonAnnotations = dsp.Spec.CommonAnnotations
+	p.PodLabels = dsp.Spec.PodLabels
+	p.PodAnnotations = dsp.Spec.PodAnnotations
+}
+
+// commonMetadataStatus returns the keys of the common labels and annotations applied to the DSPA resources. Until all
+// the resources have been applied, the keys of the previous status are kept since they may still be set on some of
+// them.
+func commonMetadataStatus(previous *dspav1.CommonMetadataStatus, params *DSPAParams, allApplied bool) *dspav1.CommonMetadataStatus {
+	if allApplied || previous == nil {
+		previous = &dspav1.CommonMetadataStatus{}
+	}
+	status := &dspav1.CommonMetadataStatus{
+		Labels:         appliedMetadataKeys(previous.Labels, params.CommonLabels),
+		Annotations:    appliedMetadataKeys(previous.Annotations, params.CommonAnnotations),
+		PodLabels:      appliedMetadataKeys(previous.PodLabels, params.PodLabels),
+		PodAnnotations: appliedMetadataKeys(previous.PodAnnotations, params.PodAnnotations),
+	}
+	if len(status.Labels)+len(status.Annotations)+len(status.PodLabels)+len(status.PodAnnotations) == 0 {
+		return nil
+	}
+	return status
+}
+
+// appliedMetadataKeys returns the sorted union of the previously applied keys and the keys of the desired metadata
+func appliedMetadataKeys(previous []string, desired map[string]string) []string {
+	keys := map[string]bool{}
+	for _, key := range previous {
+		keys[key] = true
+	}
+	for key := range desired {
+		keys[key] = true
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
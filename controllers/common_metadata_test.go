@@ -0,0 +1,106 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestCommonMetadataStatus(t *testing.T) {
+	params := &DSPAParams{
+		CommonLabels: map[string]string{"team": "ml", "cost-center": "1234"},
+		PodLabels:    map[string]string{"sidecar.istio.io/inject": "false"},
+	}
+	previous := &dspav1.CommonMetadataStatus{
+		Labels:      []string{"team", "owner"},
+		Annotations: []string{"contact"},
+	}
+
+	// Removed keys are kept until all the resources are applied
+	assert.Equal(t, &dspav1.CommonMetadataStatus{
+		Labels:      []string{"cost-center", "owner", "team"},
+		Annotations: []string{"contact"},
+		PodLabels:   []string{"sidecar.istio.io/inject"},
+	}, commonMetadataStatus(previous, params, false))
+
+	assert.Equal(t, &dspav1.CommonMetadataStatus{
+		Labels:    []string{"cost-center", "team"},
+		PodLabels: []string{"sidecar.istio.io/inject"},
+	}, commonMetadataStatus(previous, params, true))
+
+	assert.Nil(t, commonMetadataStatus(previous, &DSPAParams{}, true))
+	assert.Nil(t, commonMetadataStatus(nil, &DSPAParams{}, false))
+}
+
+func TestDeployCommonMetadata(t *testing.T) {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	dspa.Spec.CommonLabels = map[string]string{"team": "ml", "app": "not-the-app-label"}
+	dspa.Spec.CommonAnnotations = map[string]string{"contact": "ml-team@example.com"}
+	dspa.Spec.PodLabels = map[string]string{"cost-center": "1234"}
+	dspa.Spec.PodAnnotations = map[string]string{"sidecar.istio.io/inject": "false"}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, "ml", deployment.Labels["team"])
+	// Labels set by the operator take precedence
+	assert.Equal(t, "ds-pipeline-testdspa", deployment.Labels["app"])
+	assert.Equal(t, "ml-team@example.com", deployment.Annotations["contact"])
+	assert.NotContains(t, deployment.Labels, "cost-center")
+	podTemplate := deployment.Spec.Template
+	assert.Equal(t, "1234", podTemplate.Labels["cost-center"])
+	assert.Equal(t, "ds-pipeline-testdspa", podTemplate.Labels["app"])
+	assert.Equal(t, "false", podTemplate.Annotations["sidecar.istio.io/inject"])
+
+	serviceAccount := &corev1.ServiceAccount{}
+	created, err = reconciler.IsResourceCreated(ctx, serviceAccount, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, "ml", serviceAccount.Labels["team"])
+
+	role := &rbacv1.Role{}
+	created, err = reconciler.IsResourceCreated(ctx, role, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, "ml", role.Labels["team"])
+
+	// Keys removed from the spec are removed from the resources
+	dspa.Spec.CommonLabels = nil
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.NotContains(t, deployment.Labels, "team")
+	assert.Equal(t, "ml-team@example.com", deployment.Annotations["contact"])
+}
@@ -71,7 +71,7 @@ func TestDeployCommonPolicies(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Run test reconciliation
-	err = reconciler.ReconcileCommon(dspa, params)
+	err = reconciler.ReconcileCommon(ctx, dspa, params)
 	assert.Nil(t, err)
 
 	// Assert Common NetworkPolicies now exist
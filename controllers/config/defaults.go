@@ -68,12 +68,21 @@ const (
 
 	DefaultSignedUrlExpiryTimeSeconds = 60
 
+	MySQLDriver      = "mysql"
+	PostgreSQLDriver = "postgres"
+
 	MariaDBName        = "mlpipeline"
 	MariaDBHostPrefix  = "mariadb"
 	MariaDBHostPort    = "3306"
 	MariaDBUser        = "mlpipeline"
 	MariaDBNamePVCSize = "10Gi"
 
+	MariaDBBackupDefaultSchedule            = "0 2 * * *"
+	MariaDBBackupDefaultRetentionCount      = 7
+	MariaDBBackupDefaultObjectStoragePrefix = "backups/mariadb"
+	MariaDBBackupResourceNamePrefix         = "ds-pipeline-db-backup-"
+	MariaDBRestoreResourceNamePrefix        = "ds-pipeline-db-restore-"
+
 	MinioHostPrefix    = "minio"
 	MinioPort          = "9000"
 	MinioScheme        = "http"
@@ -86,6 +95,40 @@ const (
 	GeneratedObjectStorageAccessKeyLength = 16
 	GeneratedObjectStorageSecretKeyLength = 24
 
+	ObjectStorageProviderS3    = "s3"
+	ObjectStorageProviderGCS   = "gcs"
+	ObjectStorageProviderAzure = "azure"
+
+	ObjectStorageEncryptionSSES3  = "SSE-S3"
+	ObjectStorageEncryptionSSEKMS = "SSE-KMS"
+	ObjectStorageEncryptionSSEC   = "SSE-C"
+
+	ObjectStorageHealthCheckModeReadOnly  = "ReadOnly"
+	ObjectStorageHealthCheckModeReadWrite = "ReadWrite"
+	// ManagedBucketLifecycleRulePrefix is the ID prefix of the artifact expiration lifecycle rule owned by a DSPA
+	ManagedBucketLifecycleRulePrefix = "dspa-artifact-expiration-"
+	// ManagedBucketLifecycleRuleIDMaxLength is the maximum length of an S3 lifecycle rule ID
+	ManagedBucketLifecycleRuleIDMaxLength = 255
+	// ObjectStorageHealthCheckObjectPrefix is the name prefix of the probe object written by ReadWrite health checks
+	ObjectStorageHealthCheckObjectPrefix = ".dspa-health-check-"
+
+	ObjectStorageCredentialsModeStatic      = "Static"
+	ObjectStorageCredentialsModeWebIdentity = "WebIdentity"
+	DefaultWebIdentityAudience              = "sts.amazonaws.com"
+	// WebIdentityTokenMountPath is where the projected service account token is mounted in the API Server
+	WebIdentityTokenMountPath = "/var/run/secrets/dspa/serviceaccount"
+	// WebIdentityTokenExpirationSeconds is the lifetime of the tokens requested for the object storage health check
+	WebIdentityTokenExpirationSeconds = 3600
+
+	// CredentialRotationTimeAnnotation records on a generated credentials Secret when it was last rotated
+	CredentialRotationTimeAnnotation = "datasciencepipelinesapplications.opendatahub.io/credentials-rotated-at"
+	CredentialRotationHistoryLimit   = 10
+	CredentialRotationDatabase       = "Database"
+	CredentialRotationObjectStorage  = "ObjectStorage"
+
+	// CredentialRotationPendingKeySuffix suffixes the Secret key holding a database password that is being rotated
+	CredentialRotationPendingKeySuffix = ".pending"
+
 	MlmdGrpcPort = "8080"
 
 	// DefaultManagedPipelinesVolumeSizeLimit is the default emptyDir sizeLimit for the managed-pipelines volume.
@@ -120,6 +163,7 @@ const (
 	MariaDBImagePath                = "Images.MariaDB"
 	KubeRBACProxyImagePath          = "Images.KubeRBACProxy"
 	PipelinesComponentsImagePath    = "Images.PipelinesComponents"
+	MariaDBBackupImagePath          = "Images.MariaDBBackup"
 
 	// Other configs
 	ObjStoreConnectionTimeoutConfigName      = "DSPO.HealthCheck.ObjectStore.ConnectionTimeout"
@@ -139,7 +183,13 @@ const (
 	MLMDProxyReady          = "MLMDProxyReady"
 	WebhookReady            = "WebhookReady"
 	ManagedPipelineValid    = "ManagedPipelineValid"
+	DatabaseBackupHealthy   = "DatabaseBackupHealthy"
+	ManagedBucketInSync     = "ManagedBucketInSync"
+	BucketEncryptionInSync  = "BucketEncryptionInSync"
 	CrReady                 = "Ready"
+
+	// Reported when the pipelines run with an external Argo Workflows installation
+	ExternalWorkflowControllerCompatible = "ExternalWorkflowControllerCompatible"
 )
 
 // DSPA Ready Status Condition Reasons
@@ -155,6 +205,23 @@ const (
 	UnsupportedVersion          = "UnsupportedVersion"
 	ManagedPipelineInvalid      = "ManagedPipelineInvalid"
 	ManagedPipelinesFetchError  = "ManagedPipelinesFetchError"
+	DatabaseBackupFailed        = "DatabaseBackupFailed"
+	DatabaseRestoreInProgress   = "DatabaseRestoreInProgress"
+	DatabaseRestoreFailed       = "DatabaseRestoreFailed"
+	CredentialRotationFailed    = "CredentialRotationFailed"
+	MissingReadPermission       = "MissingReadPermission"
+	MissingWritePermission      = "MissingWritePermission"
+	MissingDeletePermission     = "MissingDeletePermission"
+	BucketNotFound              = "BucketNotFound"
+	BucketConfigurationDrift    = "BucketConfigurationDrift"
+	EncryptionMisconfigured     = "EncryptionMisconfigured"
+	BucketEncryptionMismatch    = "BucketEncryptionMismatch"
+	DesiredReplicasUnavailable  = "DesiredReplicasUnavailable"
+	InvalidArtifactExpiration   = "InvalidArtifactExpiration"
+	InvalidWorkflowConfig       = "InvalidWorkflowConfig"
+	ArgoWorkflowsNotFound       = "ArgoWorkflowsNotFound"
+	ArgoWorkflowsIncompatible   = "ArgoWorkflowsIncompatible"
+	ArgoWorkflowsVersionUnknown = "ArgoWorkflowsVersionUnknown"
 )
 
 // Any required Configmap paths can be added here,
@@ -174,14 +241,34 @@ const DefaultDBConnectionTimeout = time.Second * 15
 // DefaultObjStoreConnectionTimeout is the default Object storage healthcheck timeout
 const DefaultObjStoreConnectionTimeout = time.Second * 15
 
+// API Server high availability defaults
+const (
+	APIServerHighAvailabilityMinReplicas        = int32(2)
+	APIServerAutoscalingDefaultTargetCPUPercent = int32(80)
+	APIServerPodAntiAffinityWeight              = int32(100)
+	APIServerPodDisruptionBudgetMaxUnavailable  = int32(1)
+)
+
 const DefaultMaxConcurrentReconciles = 10
 
 const DefaultRequeueTime = time.Second * 20
 
+// DefaultCredentialRotationInterval is the default interval between rotations of generated credentials
+const DefaultCredentialRotationInterval = time.Hour * 24 * 90
+
+// CredentialRotationDeferredRequeueTime is the delay before a deferred rotation of the Minio keys is attempted again
+const CredentialRotationDeferredRequeueTime = time.Minute * 5
+
 const DefaultApiServerIncludeOwnerReferenceConfigName = true
 
 const DefaultPlatformVersion = "v0.0.0"
 
+// SupportedArgoWorkflowsVersions are the minor versions of an external Argo Workflows installation compatible with each
+// DSP version: the version of the workflow controller shipped with the operator and the previous one
+var SupportedArgoWorkflowsVersions = map[string][]string{
+	DSPV2VersionString: {"3.5", "3.6"},
+}
+
 const (
 	DefaultArgoWorkflowsControllersManagementState = "Managed"
 	DefaultArgoWorkflowsControllers                = "{\"managementState\":\"" + DefaultArgoWorkflowsControllersManagementState + "\"}"
@@ -0,0 +1,273 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/dspastatus"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// argoWorkflowCompletedLabel is set to "true" by the workflow controller once a workflow completed
+	argoWorkflowCompletedLabel = "workflows.argoproj.io/completed"
+)
+
+var workflowListGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "WorkflowList"}
+
+// ReconcileCredentialRotation regenerates the operator generated MariaDB password and Minio keys once they are older
+// than the configured rotation interval. It must run before the database and storage resources are applied, so that
+// the generated secrets and the deployments consuming them are rendered with the rotated credentials.
+//
+// The new MariaDB password is stored in the Secret before it is set on the running database, so that an interrupted
+// rotation can be completed. Minio only reads its root credentials at startup, so its keys are reset by the Minio
+// deployment being rolled rather than rotated through its admin API. The pipeline pods keep the keys they started
+// with, so the Minio keys are not rotated while pipeline runs are active in the namespace.
+func (r *DSPAReconciler) ReconcileCredentialRotation(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams, dspaStatus dspastatus.DSPAStatus) error {
+
+	if dsp.Spec.CredentialRotation == nil {
+		return nil
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	interval := config.DefaultCredentialRotationInterval
+	if dsp.Spec.CredentialRotation.Interval != nil && dsp.Spec.CredentialRotation.Interval.Duration > 0 {
+		interval = dsp.Spec.CredentialRotation.Interval.Duration
+	}
+
+	var rotations []dspav1.CredentialRotationRecord
+	// Generated credentials are only rotated for the deployments consuming them that the operator manages
+	if params.UsingGeneratedDBCredentials(dsp) && params.MariaDB.Deploy {
+		rotated, err := r.rotateDatabaseCredentials(ctx, dsp, params, interval)
+		if err != nil {
+			return fmt.Errorf("failed to rotate database credentials: %w", err)
+		}
+		if rotated {
+			log.Info(fmt.Sprintf("Rotated database credentials in secret [%s]", params.DBConnection.CredentialsSecret.Name))
+			rotations = append(rotations, newCredentialRotationRecord(config.CredentialRotationDatabase, params.DBConnection.CredentialsSecret.Name))
+		}
+	}
+	if params.UsingGeneratedObjectStorageCredentials(dsp) && params.Minio.Deploy {
+		rotated, err := r.rotateObjectStorageCredentials(ctx, dsp, params, interval)
+		if err != nil {
+			return fmt.Errorf("failed to rotate object storage credentials: %w", err)
+		}
+		if rotated {
+			secretName := params.ObjectStorageConnection.CredentialsSecret.SecretName
+			log.Info(fmt.Sprintf("Rotated object storage credentials in secret [%s]", secretName))
+			rotations = append(rotations, newCredentialRotationRecord(config.CredentialRotationObjectStorage, secretName))
+		}
+	}
+
+	if len(rotations) > 0 {
+		dspaStatus.AddCredentialRotations(rotations)
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) rotateDatabaseCredentials(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams, interval time.Duration) (bool, error) {
+
+	secretRef := params.DBConnection.CredentialsSecret
+	secret, due, err := r.getCredentialsSecretIfRotationDue(ctx, dsp.Namespace, secretRef.Name, interval)
+	if err != nil || secret == nil {
+		return false, err
+	}
+	pendingKey := secretRef.Key + config.CredentialRotationPendingKeySuffix
+	newPassword, pending := secret.Data[pendingKey]
+	if !due && !pending {
+		params.DBCredentialsHash = credentialsRotationHash(secret)
+		return false, nil
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	dbConnectionTimeout := config.GetDurationConfigWithDefault(config.DBConnectionTimeoutConfigName, config.DefaultDBConnectionTimeout)
+
+	var extraParams map[string]string
+	err = json.Unmarshal([]byte(params.DBConnection.ExtraParams), &extraParams)
+	if err != nil {
+		return false, err
+	}
+	tls := "false"
+	if val, ok := extraParams["tls"]; ok {
+		tls = val
+	}
+
+	// The new password is kept next to the current one until MariaDB accepts it, so that neither is lost if the
+	// rotation is interrupted. The components keep using the current password until the rotation completes.
+	if !pending {
+		newPassword = []byte(passwordGen(config.GeneratedDBPasswordLength))
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[pendingKey] = newPassword
+		if err := r.Update(ctx, secret); err != nil {
+			return false, err
+		}
+	}
+
+	alterPassword := func(currentPassword string) error {
+		return AlterMariaDBUserPassword(
+			params.DBConnection.Host,
+			log,
+			params.DBConnection.Port,
+			params.DBConnection.Username,
+			currentPassword,
+			string(newPassword),
+			tls,
+			dbConnectionTimeout,
+			params.APICustomPemCerts,
+			extraParams)
+	}
+	if err := alterPassword(params.DBConnection.DecodedPassword); err != nil {
+		// An interrupted rotation may have set the new password on MariaDB already, which then authenticates
+		if alterPassword(string(newPassword)) != nil {
+			return false, err
+		}
+	}
+
+	secret.Data[secretRef.Key] = newPassword
+	delete(secret.Data, pendingKey)
+	setCredentialRotationTime(secret)
+	if err := r.Update(ctx, secret); err != nil {
+		// The new password is still pending in the Secret, the next attempt completes the rotation
+		return false, err
+	}
+
+	params.DBConnection.Password = base64.StdEncoding.EncodeToString(newPassword)
+	params.DBConnection.DecodedPassword = string(newPassword)
+	params.DBCredentialsHash = credentialsRotationHash(secret)
+	return true, nil
+}
+
+func (r *DSPAReconciler) rotateObjectStorageCredentials(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams, interval time.Duration) (bool, error) {
+
+	secretRef := params.ObjectStorageConnection.CredentialsSecret
+	secret, due, err := r.getCredentialsSecretIfRotationDue(ctx, dsp.Namespace, secretRef.SecretName, interval)
+	if err != nil || secret == nil {
+		return false, err
+	}
+	params.ObjectStorageCredentialsHash = credentialsRotationHash(secret)
+	if !due {
+		return false, nil
+	}
+	active, err := r.hasActiveWorkflows(ctx, dsp.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if active {
+		log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+		log.Info("Deferring the rotation of the object storage credentials until the pipeline runs complete")
+		params.ObjectStorageCredentialRotationDeferred = true
+		return false, nil
+	}
+
+	accessKey := passwordGen(config.GeneratedObjectStorageAccessKeyLength)
+	secretKey := passwordGen(config.GeneratedObjectStorageSecretKeyLength)
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[secretRef.AccessKey] = []byte(accessKey)
+	secret.Data[secretRef.SecretKey] = []byte(secretKey)
+	setCredentialRotationTime(secret)
+	if err := r.Update(ctx, secret); err != nil {
+		return false, err
+	}
+
+	params.ObjectStorageConnection.AccessKeyID = base64.StdEncoding.EncodeToString([]byte(accessKey))
+	params.ObjectStorageConnection.SecretAccessKey = base64.StdEncoding.EncodeToString([]byte(secretKey))
+	params.ObjectStorageCredentialsHash = credentialsRotationHash(secret)
+	return true, nil
+}
+
+// hasActiveWorkflows reports whether the namespace has Argo workflows that did not complete
+func (r *DSPAReconciler) hasActiveWorkflows(ctx context.Context, namespace string) (bool, error) {
+	selector, err := labels.Parse(argoWorkflowCompletedLabel + "!=true")
+	if err != nil {
+		return false, err
+	}
+	workflows := &unstructured.UnstructuredList{}
+	workflows.SetGroupVersionKind(workflowListGVK)
+	err = r.List(ctx, workflows, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}, client.Limit(1))
+	if meta.IsNoMatchError(err) {
+		// Argo Workflows is not installed, no pipeline can be running
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return len(workflows.Items) > 0, nil
+}
+
+// credentialsRotationHash identifies the last rotation of a generated credentials Secret without depending on the
+// credentials. It is empty until the credentials are first rotated.
+func credentialsRotationHash(secret *corev1.Secret) string {
+	return contentHash(secret.Annotations[config.CredentialRotationTimeAnnotation])
+}
+
+// getCredentialsSecretIfRotationDue retrieves a generated credentials Secret and reports whether it was last rotated,
+// or created, more than interval ago. A Secret that does not exist yet is never due, it is generated later on.
+func (r *DSPAReconciler) getCredentialsSecretIfRotationDue(ctx context.Context, namespace, name string,
+	interval time.Duration) (*corev1.Secret, bool, error) {
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	if apierrs.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	lastRotation := secret.CreationTimestamp.Time
+	if value, ok := secret.Annotations[config.CredentialRotationTimeAnnotation]; ok {
+		if rotated, err := time.Parse(time.RFC3339, value); err == nil {
+			lastRotation = rotated
+		}
+	}
+	return secret, time.Since(lastRotation) >= interval, nil
+}
+
+func setCredentialRotationTime(secret *corev1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[config.CredentialRotationTimeAnnotation] = time.Now().UTC().Format(time.RFC3339)
+}
+
+func newCredentialRotationRecord(component, secretName string) dspav1.CredentialRotationRecord {
+	return dspav1.CredentialRotationRecord{
+		Component:    component,
+		SecretName:   secretName,
+		RotationTime: metav1.Now(),
+	}
+}
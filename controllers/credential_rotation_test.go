@@ -0,0 +1,247 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/dspastatus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func createCredentialRotationTestObjects(t *testing.T, rotatedAt time.Time) (context.Context, *DSPAParams, *DSPAReconciler, *dspav1.DataSciencePipelinesApplication) {
+	dspa := createDSPAWithDatabaseBackup(nil, true)
+	dspa.Spec.CredentialRotation = &dspav1.CredentialRotation{Interval: &metav1.Duration{Duration: 90 * 24 * time.Hour}}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, reconciler.Create(ctx, dspa))
+
+	annotations := map[string]string{config.CredentialRotationTimeAnnotation: rotatedAt.UTC().Format(time.RFC3339)}
+	dbSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-db-testdspa", Namespace: "testnamespace", Annotations: annotations},
+		Data:       map[string][]byte{"password": []byte("oldpassword")},
+	}
+	require.NoError(t, reconciler.Create(ctx, dbSecret))
+	s3Secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-s3-testdspa", Namespace: "testnamespace", Annotations: annotations},
+		Data:       map[string][]byte{"accesskey": []byte("oldaccesskey"), "secretkey": []byte("oldsecretkey")},
+	}
+	require.NoError(t, reconciler.Create(ctx, s3Secret))
+
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	return ctx, params, reconciler, dspa
+}
+
+// stubMariaDB replaces the MariaDB password change with a database holding the password "oldpassword", which
+// fails the password changes with err.
+func stubMariaDB(t *testing.T, err error) *string {
+	original := AlterMariaDBUserPassword
+	t.Cleanup(func() { AlterMariaDBUserPassword = original })
+
+	dbPassword := "oldpassword"
+	AlterMariaDBUserPassword = func(host string, log logr.Logger, port, username, password, newPassword, tls string,
+		dbConnectionTimeout time.Duration, pemCerts [][]byte, extraParams map[string]string) error {
+		if password != dbPassword {
+			return errors.New("access denied")
+		}
+		if err != nil {
+			return err
+		}
+		dbPassword = newPassword
+		return nil
+	}
+	return &dbPassword
+}
+
+func TestCredentialRotationNotDue(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-24*time.Hour))
+	dbPassword := stubMariaDB(t, nil)
+	dspaStatus := dspastatus.NewDSPAStatus(dspa)
+
+	err := reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspaStatus)
+	require.NoError(t, err)
+	assert.Equal(t, "oldpassword", *dbPassword)
+	assert.Equal(t, "oldpassword", params.DBConnection.DecodedPassword)
+	assert.NotEmpty(t, params.DBCredentialsHash)
+	assert.NotEmpty(t, params.ObjectStorageCredentialsHash)
+	assert.False(t, params.ObjectStorageCredentialRotationDeferred)
+	assert.Nil(t, dspaStatus.GetCredentialRotationStatus())
+}
+
+func TestCredentialRotationRotatesExpiredCredentials(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-91*24*time.Hour))
+	dbPassword := stubMariaDB(t, nil)
+	dspaStatus := dspastatus.NewDSPAStatus(dspa)
+
+	err := reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspaStatus)
+	require.NoError(t, err)
+
+	// The database password is changed on MariaDB and stored in the generated secret
+	assert.NotEqual(t, "oldpassword", *dbPassword)
+	assert.Equal(t, *dbPassword, params.DBConnection.DecodedPassword)
+	dbSecret := &corev1.Secret{}
+	_, err = reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, *dbPassword, string(dbSecret.Data["password"]))
+	assert.NotContains(t, dbSecret.Data, "password.pending")
+
+	// The Minio keys are regenerated
+	s3Secret := &corev1.Secret{}
+	_, err = reconciler.IsResourceCreated(ctx, s3Secret, "ds-pipeline-s3-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.NotEqual(t, "oldaccesskey", string(s3Secret.Data["accesskey"]))
+	assert.NotEqual(t, "oldsecretkey", string(s3Secret.Data["secretkey"]))
+	rotatedAt, err := time.Parse(time.RFC3339, s3Secret.Annotations[config.CredentialRotationTimeAnnotation])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), rotatedAt, time.Minute)
+
+	// The rotations are recorded with the status written at the end of the reconcile
+	credentialRotation := dspaStatus.GetCredentialRotationStatus()
+	require.NotNil(t, credentialRotation)
+	require.Len(t, credentialRotation.History, 2)
+	assert.Equal(t, config.CredentialRotationDatabase, credentialRotation.History[0].Component)
+	assert.Equal(t, config.CredentialRotationObjectStorage, credentialRotation.History[1].Component)
+	assert.Nil(t, dspa.Status.CredentialRotation)
+
+	// Rotation is not repeated until the interval has elapsed again
+	dbHash, objectStorageHash := params.DBCredentialsHash, params.ObjectStorageCredentialsHash
+	rotatedPassword := *dbPassword
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspaStatus))
+	assert.Equal(t, rotatedPassword, *dbPassword)
+	assert.Equal(t, dbHash, params.DBCredentialsHash)
+	assert.Equal(t, objectStorageHash, params.ObjectStorageCredentialsHash)
+}
+
+func TestCredentialRotationKeepsSecretWhenDatabaseUpdateFails(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-91*24*time.Hour))
+	stubMariaDB(t, errors.New("read only"))
+
+	err := reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa))
+	assert.EqualError(t, err, "failed to rotate database credentials: read only")
+
+	// The components keep using the current password, the new one is pending until MariaDB accepts it
+	dbSecret := &corev1.Secret{}
+	_, err = reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, "oldpassword", string(dbSecret.Data["password"]))
+	pendingPassword := string(dbSecret.Data["password.pending"])
+	require.NotEmpty(t, pendingPassword)
+
+	dbPassword := stubMariaDB(t, nil)
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa)))
+	assert.Equal(t, pendingPassword, *dbPassword)
+	_, err = reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, pendingPassword, string(dbSecret.Data["password"]))
+}
+
+func TestCredentialRotationCompletesInterruptedRotation(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-24*time.Hour))
+	// MariaDB accepted the pending password, but the Secret was not updated with it
+	dbPassword := stubMariaDB(t, nil)
+	*dbPassword = "newpassword"
+	dbSecret := &corev1.Secret{}
+	_, err := reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	dbSecret.Data["password.pending"] = []byte("newpassword")
+	require.NoError(t, reconciler.Update(ctx, dbSecret))
+
+	err = reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa))
+	require.NoError(t, err)
+	assert.Equal(t, "newpassword", params.DBConnection.DecodedPassword)
+	_, err = reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, "newpassword", string(dbSecret.Data["password"]))
+	assert.NotContains(t, dbSecret.Data, "password.pending")
+}
+
+func TestCredentialRotationDisabled(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-365*24*time.Hour))
+	dspa.Spec.CredentialRotation = nil
+	dbPassword := stubMariaDB(t, nil)
+
+	err := reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa))
+	require.NoError(t, err)
+	assert.Equal(t, "oldpassword", *dbPassword)
+	assert.Empty(t, params.DBCredentialsHash)
+	assert.Empty(t, params.ObjectStorageCredentialsHash)
+}
+
+func TestCredentialRotationHashesAreScopedToTheRotatedCredentials(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-24*time.Hour))
+	stubMariaDB(t, nil)
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa)))
+	dbHash, objectStorageHash := params.DBCredentialsHash, params.ObjectStorageCredentialsHash
+
+	// Only the database password is due, Minio and the components only using the Minio keys are not rolled
+	dbSecret := &corev1.Secret{}
+	_, err := reconciler.IsResourceCreated(ctx, dbSecret, "ds-pipeline-db-testdspa", "testnamespace")
+	require.NoError(t, err)
+	dbSecret.Annotations[config.CredentialRotationTimeAnnotation] = time.Now().Add(-91 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, reconciler.Update(ctx, dbSecret))
+
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa)))
+	assert.NotEqual(t, dbHash, params.DBCredentialsHash)
+	assert.Equal(t, objectStorageHash, params.ObjectStorageCredentialsHash)
+}
+
+func TestCredentialRotationDefersObjectStorageWhileRunsAreActive(t *testing.T) {
+	ctx, params, reconciler, dspa := createCredentialRotationTestObjects(t, time.Now().Add(-91*24*time.Hour))
+	stubMariaDB(t, nil)
+	workflowGVK := workflowListGVK.GroupVersion().WithKind("Workflow")
+	reconciler.Scheme.AddKnownTypeWithName(workflowGVK, &unstructured.Unstructured{})
+	reconciler.Scheme.AddKnownTypeWithName(workflowListGVK, &unstructured.UnstructuredList{})
+
+	workflow := &unstructured.Unstructured{}
+	workflow.SetGroupVersionKind(workflowGVK)
+	workflow.SetName("run")
+	workflow.SetNamespace("testnamespace")
+	require.NoError(t, reconciler.Create(ctx, workflow))
+
+	dspaStatus := dspastatus.NewDSPAStatus(dspa)
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspaStatus))
+	assert.True(t, params.ObjectStorageCredentialRotationDeferred)
+	s3Secret := &corev1.Secret{}
+	_, err := reconciler.IsResourceCreated(ctx, s3Secret, "ds-pipeline-s3-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, "oldaccesskey", string(s3Secret.Data["accesskey"]))
+	// The database password does not depend on the pipeline runs
+	require.Len(t, dspaStatus.GetCredentialRotationStatus().History, 1)
+	assert.Equal(t, config.CredentialRotationDatabase, dspaStatus.GetCredentialRotationStatus().History[0].Component)
+
+	// The keys are rotated once the runs completed
+	workflow.SetLabels(map[string]string{argoWorkflowCompletedLabel: "true"})
+	require.NoError(t, reconciler.Update(ctx, workflow))
+	params.ObjectStorageCredentialRotationDeferred = false
+	require.NoError(t, reconciler.ReconcileCredentialRotation(ctx, dspa, params, dspastatus.NewDSPAStatus(dspa)))
+	assert.False(t, params.ObjectStorageCredentialRotationDeferred)
+	_, err = reconciler.IsResourceCreated(ctx, s3Secret, "ds-pipeline-s3-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.NotEqual(t, "oldaccesskey", string(s3Secret.Data["accesskey"]))
+}
@@ -28,9 +28,13 @@ import (
 	"errors"
 
 	"os"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -109,14 +113,92 @@ func createMySQLConfig(user, password string, mysqlServiceHost string,
 	}
 }
 
+// createPostgreSQLConfig builds a pgx connection config from the DSPA connection values.
+// extraParams are appended as libpq connection keywords (e.g. sslmode, connect_timeout).
+func createPostgreSQLConfig(user, password, host, port, dbName string, extraParams map[string]string) (*pgx.ConnConfig, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s",
+		pgQuote(host), pgQuote(port), pgQuote(user), pgQuote(password))
+	if dbName != "" {
+		dsn += fmt.Sprintf(" dbname=%s", pgQuote(dbName))
+	}
+	for k, v := range extraParams {
+		dsn += fmt.Sprintf(" %s=%s", k, pgQuote(v))
+	}
+	return pgx.ParseConfig(dsn)
+}
+
+// pgQuote quotes a libpq keyword value so that values containing spaces,
+// quotes or backslashes are parsed correctly.
+func pgQuote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
 var ConnectAndQueryDatabase = func(
 	host string,
 	log logr.Logger,
-	port, username, password, dbname, tls string,
+	driver, port, username, password, dbname, tls string,
 	dbConnectionTimeout time.Duration,
 	pemCerts [][]byte,
 	extraParams map[string]string) (bool, error) {
 
+	if driver == config.PostgreSQLDriver {
+		return connectAndQueryPostgreSQL(host, log, port, username, password, dbname, tls, dbConnectionTimeout, pemCerts, extraParams)
+	}
+
+	// Create a context with a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
+	defer cancel()
+
+	db, err := openMySQLDatabase(host, log, port, username, password, tls, pemCerts, extraParams)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	testStatement := "SELECT 1;"
+	_, err = db.QueryContext(ctx, testStatement)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AlterMariaDBUserPassword changes the password of the connecting user on the MariaDB deployed by the operator.
+// SET PASSWORD is used rather than ALTER USER as it does not require the CREATE USER privilege, its PASSWORD()
+// function is not available on MySQL 8, so it must not be used for external databases.
+var AlterMariaDBUserPassword = func(
+	host string,
+	log logr.Logger,
+	port, username, password, newPassword, tls string,
+	dbConnectionTimeout time.Duration,
+	pemCerts [][]byte,
+	extraParams map[string]string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
+	defer cancel()
+
+	db, err := openMySQLDatabase(host, log, port, username, password, tls, pemCerts, extraParams)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(newPassword)
+	_, err = db.ExecContext(ctx, fmt.Sprintf("SET PASSWORD = PASSWORD('%s');", escaped))
+	return err
+}
+
+// openMySQLDatabase opens a MySQL connection pool, registering a custom TLS config when tls is
+// one of "true", "skip-verify" or "preferred".
+func openMySQLDatabase(
+	host string,
+	log logr.Logger,
+	port, username, password, tls string,
+	pemCerts [][]byte,
+	extraParams map[string]string) (*sql.DB, error) {
+
 	mysqlConfig := createMySQLConfig(
 		username,
 		password,
@@ -126,10 +208,6 @@ var ConnectAndQueryDatabase = func(
 		extraParams,
 	)
 
-	// Create a context with a timeout
-	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
-	defer cancel()
-
 	var tlsConfig *cryptoTls.Config
 	switch tls {
 	case "false", "":
@@ -139,7 +217,7 @@ var ConnectAndQueryDatabase = func(
 		tlsConfig, err = tLSClientConfig(pemCerts)
 		if err != nil {
 			log.Info(fmt.Sprintf("Encountered error when processing custom ca bundle, Error: %v", err))
-			return false, err
+			return nil, err
 		}
 	case "skip-verify", "preferred":
 		tlsConfig = &cryptoTls.Config{InsecureSkipVerify: true}
@@ -158,24 +236,137 @@ var ConnectAndQueryDatabase = func(
 		// Just to be safe, we also set it here, fallback from mysqlConfig.Params["tls"] not being set
 		mysqlConfig.TLSConfig = "custom"
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 	}
 
-	db, err := sql.Open("mysql", mysqlConfig.FormatDSN())
+	return sql.Open("mysql", mysqlConfig.FormatDSN())
+}
+
+// connectAndQueryPostgreSQL performs the health check query against a PostgreSQL database.
+// sslMode follows libpq semantics: "disable" does not use TLS, "allow" tries plaintext then TLS,
+// "prefer" tries TLS then plaintext, "require" uses TLS, none of them verify the server
+// certificate. "verify-ca" and "verify-full" verify it against the system certs plus any custom
+// CA bundles provided to the DSPA.
+func connectAndQueryPostgreSQL(
+	host string,
+	log logr.Logger,
+	port, username, password, dbname, sslMode string,
+	dbConnectionTimeout time.Duration,
+	pemCerts [][]byte,
+	extraParams map[string]string) (bool, error) {
+
+	pgConfig, err := healthCheckPostgreSQLConfig(host, port, username, password, dbname, sslMode, pemCerts, extraParams)
 	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when building the PostgreSQL connection config, Error: %v", err))
 		return false, err
 	}
+
+	// Create a context with a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
+	defer cancel()
+
+	db := stdlib.OpenDB(*pgConfig)
 	defer db.Close()
 
 	testStatement := "SELECT 1;"
-	_, err = db.QueryContext(ctx, testStatement)
+	_, err = db.ExecContext(ctx, testStatement)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
+// healthCheckPostgreSQLConfig returns the connection config of the PostgreSQL health check for the sslmode
+func healthCheckPostgreSQLConfig(host, port, username, password, dbname, sslMode string, pemCerts [][]byte,
+	extraParams map[string]string) (*pgx.ConnConfig, error) {
+
+	// sslmode is handled below so that the custom CA bundles can be applied
+	params := map[string]string{}
+	for k, v := range extraParams {
+		if k != "sslmode" && k != "sslrootcert" {
+			params[k] = v
+		}
+	}
+	params["sslmode"] = "disable"
+
+	pgConfig, err := createPostgreSQLConfig(username, password, host, port, dbname, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfigs, err := postgreSQLTLSConfigs(sslMode, host, pemCerts)
+	if err != nil {
+		return nil, err
+	}
+	// Like libpq, "prefer" falls back to plaintext and "allow" falls back to TLS when the first attempt fails
+	pgConfig.TLSConfig = tlsConfigs[0]
+	for _, tlsConfig := range tlsConfigs[1:] {
+		pgConfig.Fallbacks = append(pgConfig.Fallbacks, &pgconn.FallbackConfig{
+			Host:      pgConfig.Host,
+			Port:      pgConfig.Port,
+			TLSConfig: tlsConfig,
+		})
+	}
+	return pgConfig, nil
+}
+
+// postgreSQLTLSConfigs returns the TLS configurations of the connection attempts for the sslmode, in order. A nil
+// configuration is a plaintext attempt.
+func postgreSQLTLSConfigs(sslMode, host string, pemCerts [][]byte) ([]*cryptoTls.Config, error) {
+	switch sslMode {
+	case "verify-ca", "verify-full":
+		tlsConfig, err := tLSClientConfig(pemCerts)
+		if err != nil {
+			return nil, err
+		}
+		if sslMode == "verify-full" {
+			tlsConfig.ServerName = host
+		} else {
+			// verify-ca only checks the chain, not the hostname
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyPeerCertificate = verifyPeerCertificateChain(tlsConfig.RootCAs)
+		}
+		return []*cryptoTls.Config{tlsConfig}, nil
+	case "require":
+		return []*cryptoTls.Config{{InsecureSkipVerify: true}}, nil
+	case "prefer":
+		return []*cryptoTls.Config{{InsecureSkipVerify: true}, nil}, nil
+	case "allow":
+		return []*cryptoTls.Config{nil, {InsecureSkipVerify: true}}, nil
+	default:
+		// "disable", or an unknown sslmode
+		return []*cryptoTls.Config{nil}, nil
+	}
+}
+
+// verifyPeerCertificateChain verifies the server certificate chain against roots
+// without verifying the hostname, matching the libpq "verify-ca" sslmode.
+func verifyPeerCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no server certificate presented")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
 func (r *DSPAReconciler) isDatabaseAccessible(dsp *dspav1.DataSciencePipelinesApplication,
 	params *DSPAParams) (bool, error) {
 	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
@@ -222,11 +413,21 @@ func (r *DSPAReconciler) isDatabaseAccessible(dsp *dspav1.DataSciencePipelinesAp
 		tls = val
 	}
 
+	// For PostgreSQL the equivalent setting is the libpq sslmode, which
+	// defaults to verify-full for external databases
+	if params.UsingPostgreSQL() {
+		tls = "verify-full"
+		if val, ok := extraParamsJson["sslmode"]; ok {
+			tls = val
+		}
+	}
+
 	log.V(1).Info(fmt.Sprintf("Attempting Database Heath Check connection (with timeout: %s)", dbConnectionTimeout))
 
 	dbHealthCheckPassed, err := ConnectAndQueryDatabase(
 		params.DBConnection.Host,
 		log,
+		params.DBConnection.Driver,
 		params.DBConnection.Port,
 		params.DBConnection.Username,
 		string(decodePass),
@@ -270,6 +471,10 @@ func (r *DSPAReconciler) ReconcileDatabase(ctx context.Context, dsp *dspav1.Data
 	// If external db is specified, it takes precedence
 	if externalDBSpecified {
 		log.Info("Using externalDB, bypassing database deployment.")
+		// Clean up any restore Job left over from a previously deployed MariaDB
+		if err := r.ReconcileDatabaseRestore(ctx, dsp, params); err != nil {
+			return err
+		}
 	} else if deployMariaDB || deployDefaultDB {
 		if !databaseCredentialsProvided {
 			err := r.Apply(dsp, params, dbSecret)
@@ -278,12 +483,20 @@ func (r *DSPAReconciler) ReconcileDatabase(ctx context.Context, dsp *dspav1.Data
 			}
 		}
 		log.Info("Applying mariaDB resources.")
+		err := r.reconcileCertificate(ctx, dsp, params, mariaDBCertificate, mariaDBCertificateSecretNamePrefix+dsp.Name, params.PodToPodTLS)
+		if err != nil {
+			return err
+		}
 		for _, template := range mariadbTemplates {
 			err := r.Apply(dsp, params, template)
 			if err != nil {
 				return err
 			}
 		}
+		// The restore Job waits for the MariaDB deployment above to become reachable
+		if err := r.ReconcileDatabaseRestore(ctx, dsp, params); err != nil {
+			return err
+		}
 		// If no database was not specified, deploy mariaDB by default.
 		// Update the CR with the state of mariaDB to accurately portray
 		// desired state.
@@ -300,7 +513,7 @@ func (r *DSPAReconciler) ReconcileDatabase(ctx context.Context, dsp *dspav1.Data
 	} else {
 		log.Info("No externalDB detected, and mariaDB disabled. " +
 			"skipping Application of DB Resources")
-		return nil
+		return r.ReconcileDatabaseRestore(ctx, dsp, params)
 	}
 	log.Info("Finished applying Database Resources")
 
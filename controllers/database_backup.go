@@ -0,0 +1,131 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const dbBackupTemplate = "mariadb/backup/cronjob.yaml.tmpl"
+
+// ReconcileDatabaseBackup applies the MariaDB backup CronJob when scheduled backups are configured
+// for an operator deployed MariaDB, and removes it otherwise. Returns true if backups are enabled.
+func (r *DSPAReconciler) ReconcileDatabaseBackup(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, error) {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if !params.UsingMariaDBBackup(dsp) {
+		cronJob := &batchv1.CronJob{}
+		nn := types.NamespacedName{Name: config.MariaDBBackupResourceNamePrefix + dsp.Name, Namespace: dsp.Namespace}
+		return false, r.DeleteResourceIfItExists(ctx, cronJob, nn)
+	}
+
+	backup := params.MariaDB.Backup
+	if backup.PVCName == "" && (backup.Image == "" || backup.Image == config.DefaultImageValue) {
+		return true, fmt.Errorf("database backup upload image not configured: specify spec.database.mariaDB.backup.image " +
+			"or configure operator Images.MariaDBBackup (IMAGES_MARIADB_BACKUP in DSPO params/config)")
+	}
+	if backup.PVCName == "" && params.ObjectStorageConnection.CredentialsSecret == nil {
+		return true, fmt.Errorf("database backups to object storage require static s3 object storage credentials: " +
+			"specify spec.database.mariaDB.backup.pvcName when using another externalStorage provider or credentialsMode")
+	}
+
+	log.Info("Applying Database Backup Resources")
+	err := r.Apply(dsp, params, dbBackupTemplate)
+	if err != nil {
+		return true, err
+	}
+
+	log.Info("Finished applying Database Backup Resources")
+	return true, nil
+}
+
+// checkDatabaseBackupStatus reports whether the most recent finished backup Job succeeded, along with a
+// message describing the last successful backup, or the failure of the most recent one.
+func (r *DSPAReconciler) checkDatabaseBackupStatus(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication) (bool, string) {
+	cronJobName := config.MariaDBBackupResourceNamePrefix + dsp.Name
+
+	cronJob := &batchv1.CronJob{}
+	err := r.Get(ctx, types.NamespacedName{Name: cronJobName, Namespace: dsp.Namespace}, cronJob)
+	if err != nil {
+		return false, fmt.Sprintf("Unable to retrieve database backup CronJob %s: %v", cronJobName, err)
+	}
+
+	jobs := &batchv1.JobList{}
+	err = r.List(ctx, jobs, client.InNamespace(dsp.Namespace), client.MatchingLabels{"app": cronJobName})
+	if err != nil {
+		return false, fmt.Sprintf("Unable to list database backup Jobs: %v", err)
+	}
+
+	var lastFinished *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if getJobFinishedCondition(job) == nil {
+			continue
+		}
+		if lastFinished == nil || lastFinished.CreationTimestamp.Before(&job.CreationTimestamp) {
+			lastFinished = job
+		}
+	}
+
+	if lastFinished != nil {
+		finished := getJobFinishedCondition(lastFinished)
+		if finished.Type == batchv1.JobFailed {
+			return false, fmt.Sprintf("Most recent database backup Job %s failed: %s", lastFinished.Name, finished.Message)
+		}
+	}
+
+	if cronJob.Status.LastSuccessfulTime != nil {
+		return true, fmt.Sprintf("Last database backup completed successfully at %s",
+			cronJob.Status.LastSuccessfulTime.UTC().Format(time.RFC3339))
+	}
+	return true, "Database backups are scheduled, no backup has completed yet"
+}
+
+// GetDatabaseBackupStatus returns the backup times recorded on the DSPA's backup CronJob,
+// or nil if no backup CronJob exists.
+func (r *DSPAReconciler) GetDatabaseBackupStatus(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication) *dspav1.DatabaseBackupStatus {
+	cronJob := &batchv1.CronJob{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.MariaDBBackupResourceNamePrefix + dsp.Name, Namespace: dsp.Namespace}, cronJob)
+	if err != nil {
+		return nil
+	}
+	return &dspav1.DatabaseBackupStatus{
+		LastSuccessfulBackupTime: cronJob.Status.LastSuccessfulTime,
+		LastScheduleTime:         cronJob.Status.LastScheduleTime,
+	}
+}
+
+// getJobFinishedCondition returns the Complete or Failed condition of a Job, or nil if it is still running.
+func getJobFinishedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		c := &job.Status.Conditions[i]
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return c
+		}
+	}
+	return nil
+}
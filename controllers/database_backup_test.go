@@ -0,0 +1,195 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createDSPAWithDatabaseBackup(backup *dspav1.MariaDBBackup, deployMinio bool) *dspav1.DataSciencePipelinesApplication {
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			Database: &dspav1.Database{
+				DisableHealthCheck: false,
+				MariaDB: &dspav1.MariaDB{
+					Deploy: true,
+					Backup: backup,
+				},
+			},
+			ObjectStorage: &dspav1.ObjectStorage{
+				DisableHealthCheck: false,
+				Minio: &dspav1.Minio{
+					Deploy: deployMinio,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = "testnamespace"
+	return dspa
+}
+
+func TestDeployDatabaseBackupToPVC(t *testing.T) {
+	expectedCronJobName := "ds-pipeline-db-backup-testdspa"
+	dspa := createDSPAWithDatabaseBackup(&dspav1.MariaDBBackup{PVCName: "backup-pvc"}, false)
+	dspa.Spec.DSPVersion = "v2"
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	enabled, err := reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	cronJob := &batchv1.CronJob{}
+	created, err := reconciler.IsResourceCreated(ctx, cronJob, expectedCronJobName, "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	assert.Equal(t, config.MariaDBBackupDefaultSchedule, cronJob.Spec.Schedule)
+	// The Jobs of the backup are cached by the operator
+	assert.Equal(t, "v2", cronJob.Spec.JobTemplate.Labels[config.DSPVersionk8sLabel])
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "mysqldump", podSpec.Containers[0].Name)
+	require.NotNil(t, podSpec.Volumes[0].PersistentVolumeClaim)
+	assert.Equal(t, "backup-pvc", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+}
+
+func TestDeployDatabaseBackupToObjectStorage(t *testing.T) {
+	expectedCronJobName := "ds-pipeline-db-backup-testdspa"
+	dspa := createDSPAWithDatabaseBackup(&dspav1.MariaDBBackup{
+		Schedule:       "0 */6 * * *",
+		RetentionCount: 3,
+		Image:          "mc:test",
+	}, true)
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	enabled, err := reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	cronJob := &batchv1.CronJob{}
+	created, err := reconciler.IsResourceCreated(ctx, cronJob, expectedCronJobName, "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	assert.Equal(t, "0 */6 * * *", cronJob.Spec.Schedule)
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, "mysqldump", podSpec.InitContainers[0].Name)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "mc:test", podSpec.Containers[0].Image)
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "S3_PREFIX", Value: config.MariaDBBackupDefaultObjectStoragePrefix})
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "RETENTION_COUNT", Value: "3"})
+}
+
+func TestDatabaseBackupRequiresUploadImage(t *testing.T) {
+	dspa := createDSPAWithDatabaseBackup(&dspav1.MariaDBBackup{}, true)
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	enabled, err := reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	assert.True(t, enabled)
+	assert.Error(t, err)
+}
+
+func TestDontDeployDatabaseBackup(t *testing.T) {
+	expectedCronJobName := "ds-pipeline-db-backup-testdspa"
+	dspa := createDSPAWithDatabaseBackup(&dspav1.MariaDBBackup{PVCName: "backup-pvc"}, false)
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+	_, err = reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	require.NoError(t, err)
+
+	// Removing the backup configuration removes the CronJob
+	dspa.Spec.Database.MariaDB.Backup = nil
+	params.MariaDB = dspa.Spec.Database.MariaDB.DeepCopy()
+	enabled, err := reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	created, err := reconciler.IsResourceCreated(ctx, &batchv1.CronJob{}, expectedCronJobName, "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestCheckDatabaseBackupStatus(t *testing.T) {
+	dspa := createDSPAWithDatabaseBackup(&dspav1.MariaDBBackup{PVCName: "backup-pvc"}, false)
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+	_, err = reconciler.ReconcileDatabaseBackup(ctx, dspa, params)
+	require.NoError(t, err)
+
+	healthy, message := reconciler.checkDatabaseBackupStatus(ctx, dspa)
+	assert.True(t, healthy)
+	assert.Contains(t, message, "no backup has completed yet")
+
+	// Record a successful backup on the CronJob
+	cronJob := &batchv1.CronJob{}
+	_, err = reconciler.IsResourceCreated(ctx, cronJob, "ds-pipeline-db-backup-testdspa", "testnamespace")
+	require.NoError(t, err)
+	lastSuccess := metav1.Now()
+	cronJob.Status.LastSuccessfulTime = &lastSuccess
+	require.NoError(t, reconciler.Status().Update(ctx, cronJob))
+
+	healthy, message = reconciler.checkDatabaseBackupStatus(ctx, dspa)
+	assert.True(t, healthy)
+	assert.Contains(t, message, "completed successfully")
+	backupStatus := reconciler.GetDatabaseBackupStatus(ctx, dspa)
+	require.NotNil(t, backupStatus)
+	require.NotNil(t, backupStatus.LastSuccessfulBackupTime)
+
+	// A failed Job more recent than the last success makes the backup unhealthy
+	failedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ds-pipeline-db-backup-testdspa-1",
+			Namespace: "testnamespace",
+			Labels:    map[string]string{"app": "ds-pipeline-db-backup-testdspa"},
+		},
+	}
+	require.NoError(t, reconciler.Create(ctx, failedJob))
+	failedJob.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+	}
+	require.NoError(t, reconciler.Status().Update(ctx, failedJob))
+
+	healthy, message = reconciler.checkDatabaseBackupStatus(ctx, dspa)
+	assert.False(t, healthy)
+	assert.Contains(t, message, "BackoffLimitExceeded")
+}
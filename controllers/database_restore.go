@@ -0,0 +1,105 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const dbRestoreTemplate = "mariadb/restore/job.yaml.tmpl"
+
+// ReconcileDatabaseRestore creates the one-shot MariaDB restore Job when restoreFrom is configured for an
+// operator deployed MariaDB, and removes it otherwise. Job specs are immutable, so an existing Job is never
+// re-applied; the restore itself is skipped by the Job when the target database already contains tables, unless
+// they were left behind by a failed import of the Job.
+func (r *DSPAReconciler) ReconcileDatabaseRestore(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	nn := types.NamespacedName{Name: config.MariaDBRestoreResourceNamePrefix + dsp.Name, Namespace: dsp.Namespace}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, nn, job)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	jobExists := err == nil
+
+	if !params.UsingMariaDBRestore(dsp) {
+		if !jobExists {
+			return nil
+		}
+		// Jobs are orphaned by default, make sure the restore pods are cleaned up with the Job
+		err = r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if jobExists {
+		return nil
+	}
+
+	restore := params.MariaDB.RestoreFrom
+	if restore.PVCName == "" && (restore.Image == "" || restore.Image == config.DefaultImageValue) {
+		return fmt.Errorf("database restore download image not configured: specify spec.database.mariaDB.restoreFrom.image " +
+			"or configure operator Images.MariaDBBackup (IMAGES_MARIADB_BACKUP in DSPO params/config)")
+	}
+	if restore.PVCName == "" && params.ObjectStorageConnection.CredentialsSecret == nil {
+		return fmt.Errorf("database restores from object storage require static s3 object storage credentials: " +
+			"specify spec.database.mariaDB.restoreFrom.pvcName when using another externalStorage provider or credentialsMode")
+	}
+
+	log.Info("Applying Database Restore Job")
+	return r.Apply(dsp, params, dbRestoreTemplate)
+}
+
+// checkDatabaseRestoreStatus reports whether the API Server may be deployed with respect to a configured
+// database restore. When it may not, the returned reason and message describe the state of the restore Job.
+func (r *DSPAReconciler) checkDatabaseRestoreStatus(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, string, string) {
+
+	if !params.UsingMariaDBRestore(dsp) {
+		return true, "", ""
+	}
+
+	jobName := config.MariaDBRestoreResourceNamePrefix + dsp.Name
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: dsp.Namespace}, job)
+	if err != nil {
+		return false, config.DatabaseRestoreInProgress, fmt.Sprintf("Unable to retrieve database restore Job %s: %v", jobName, err)
+	}
+
+	finished := getJobFinishedCondition(job)
+	switch {
+	case finished == nil:
+		return false, config.DatabaseRestoreInProgress, fmt.Sprintf("Waiting for database restore Job %s to complete", jobName)
+	case finished.Type == batchv1.JobFailed:
+		return false, config.DatabaseRestoreFailed, fmt.Sprintf("Database restore Job %s failed: %s", jobName, finished.Message)
+	default:
+		return true, "", ""
+	}
+}
@@ -0,0 +1,231 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func createDSPAWithDatabaseRestore(restore *dspav1.MariaDBRestoreSource) *dspav1.DataSciencePipelinesApplication {
+	dspa := createDSPAWithDatabaseBackup(nil, true)
+	dspa.Spec.Database.MariaDB.RestoreFrom = restore
+	return dspa
+}
+
+func TestDeployDatabaseRestoreFromPVC(t *testing.T) {
+	expectedJobName := "ds-pipeline-db-restore-testdspa"
+	dspa := createDSPAWithDatabaseRestore(&dspav1.MariaDBRestoreSource{PVCName: "backup-pvc", Path: "mlpipeline-20250101T020000Z.sql"})
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	err = reconciler.ReconcileDatabaseRestore(ctx, dspa, params)
+	require.NoError(t, err)
+
+	job := &batchv1.Job{}
+	created, err := reconciler.IsResourceCreated(ctx, job, expectedJobName, "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "DUMP_FILE", Value: "/restore/mlpipeline-20250101T020000Z.sql"})
+	require.NotNil(t, podSpec.Volumes[0].PersistentVolumeClaim)
+	assert.Equal(t, "backup-pvc", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+}
+
+func TestDeployDatabaseRestoreFromObjectStorage(t *testing.T) {
+	expectedJobName := "ds-pipeline-db-restore-testdspa"
+	dspa := createDSPAWithDatabaseRestore(&dspav1.MariaDBRestoreSource{
+		ObjectStorageKey: "/backups/mariadb/mlpipeline-20250101T020000Z.sql",
+		Image:            "mc:test",
+	})
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	err = reconciler.ReconcileDatabaseRestore(ctx, dspa, params)
+	require.NoError(t, err)
+
+	job := &batchv1.Job{}
+	created, err := reconciler.IsResourceCreated(ctx, job, expectedJobName, "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	podSpec := job.Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, "mc:test", podSpec.InitContainers[0].Image)
+	assert.Contains(t, podSpec.InitContainers[0].Env, corev1.EnvVar{Name: "S3_KEY", Value: "backups/mariadb/mlpipeline-20250101T020000Z.sql"})
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+}
+
+func TestDatabaseRestoreRequiresDownloadImage(t *testing.T) {
+	dspa := createDSPAWithDatabaseRestore(&dspav1.MariaDBRestoreSource{ObjectStorageKey: "backups/dump.sql"})
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	err = reconciler.ReconcileDatabaseRestore(ctx, dspa, params)
+	assert.Error(t, err)
+}
+
+func TestCheckDatabaseRestoreStatus(t *testing.T) {
+	expectedJobName := "ds-pipeline-db-restore-testdspa"
+	dspa := createDSPAWithDatabaseRestore(&dspav1.MariaDBRestoreSource{PVCName: "backup-pvc", Path: "dump.sql"})
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	// Restore Job not created yet
+	restored, reason, _ := reconciler.checkDatabaseRestoreStatus(ctx, dspa, params)
+	assert.False(t, restored)
+	assert.Equal(t, config.DatabaseRestoreInProgress, reason)
+
+	require.NoError(t, reconciler.ReconcileDatabaseRestore(ctx, dspa, params))
+	restored, reason, _ = reconciler.checkDatabaseRestoreStatus(ctx, dspa, params)
+	assert.False(t, restored)
+	assert.Equal(t, config.DatabaseRestoreInProgress, reason)
+
+	job := &batchv1.Job{}
+	_, err = reconciler.IsResourceCreated(ctx, job, expectedJobName, "testnamespace")
+	require.NoError(t, err)
+	job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+	}
+	require.NoError(t, reconciler.Status().Update(ctx, job))
+
+	restored, reason, message := reconciler.checkDatabaseRestoreStatus(ctx, dspa, params)
+	assert.False(t, restored)
+	assert.Equal(t, config.DatabaseRestoreFailed, reason)
+	assert.Contains(t, message, "BackoffLimitExceeded")
+
+	job.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+	}
+	require.NoError(t, reconciler.Status().Update(ctx, job))
+	restored, _, _ = reconciler.checkDatabaseRestoreStatus(ctx, dspa, params)
+	assert.True(t, restored)
+
+	// Removing restoreFrom removes the Job and no longer gates the API Server
+	dspa.Spec.Database.MariaDB.RestoreFrom = nil
+	params.MariaDB = dspa.Spec.Database.MariaDB.DeepCopy()
+	require.NoError(t, reconciler.ReconcileDatabaseRestore(ctx, dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &batchv1.Job{}, expectedJobName, "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+	restored, _, _ = reconciler.checkDatabaseRestoreStatus(ctx, dspa, params)
+	assert.True(t, restored)
+}
+
+// fakeMySQL emulates the mysql client for the restore script, the tables of the database are kept one per line in
+// $STATE/tables. A dump line "CREATE <table>" creates a table, a dump line "FAIL" fails the import.
+const fakeMySQL = `#!/bin/bash
+query=
+while [ $# -gt 0 ]; do
+  if [ "$1" = -e ]; then query=$2; shift; fi
+  shift
+done
+case "$query" in
+"")
+  while read -r statement table; do
+    case "$statement" in
+    CREATE) echo "$table" >> "$STATE/tables" ;;
+    FAIL) exit 1 ;;
+    esac
+  done ;;
+"SELECT table_name"*) cat "$STATE/tables" ;;
+*"DROP TABLE"*)
+  table=${query##* }
+  table=${table//\` + "`" + `/}
+  grep -vx "$table" "$STATE/tables" > "$STATE/remaining"
+  mv "$STATE/remaining" "$STATE/tables" ;;
+"CREATE TABLE "*)
+  table=${query#CREATE TABLE }
+  echo "${table%% *}" >> "$STATE/tables" ;;
+esac
+`
+
+func TestDatabaseRestoreRetry(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is required to run the restore script")
+	}
+	dspa := createDSPAWithDatabaseRestore(&dspav1.MariaDBRestoreSource{PVCName: "backup-pvc", Path: "dump.sql"})
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileDatabaseRestore(ctx, dspa, params))
+	job := &batchv1.Job{}
+	created, err := reconciler.IsResourceCreated(ctx, job, "ds-pipeline-db-restore-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	script := job.Spec.Template.Spec.Containers[0].Args[0]
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	require.NoError(t, os.Mkdir(bin, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mysql"), []byte(fakeMySQL), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mysqladmin"), []byte("#!/bin/sh\n"), 0o755))
+	tablesFile := filepath.Join(dir, "tables")
+	dumpFile := filepath.Join(dir, "dump.sql")
+
+	restore := func(dump string) error {
+		require.NoError(t, os.WriteFile(dumpFile, []byte(dump), 0o644))
+		cmd := exec.Command("bash", "-c", script)
+		cmd.Env = append(os.Environ(), "PATH="+bin+":"+os.Getenv("PATH"), "STATE="+dir, "DUMP_FILE="+dumpFile,
+			"DB_NAME=mlpipeline")
+		return cmd.Run()
+	}
+	tables := func() []string {
+		content, err := os.ReadFile(tablesFile)
+		require.NoError(t, err)
+		return strings.Fields(string(content))
+	}
+
+	// A failed import leaves its tables and the marker behind
+	require.NoError(t, os.WriteFile(tablesFile, nil, 0o644))
+	assert.Error(t, restore("CREATE run_details\nFAIL\n"))
+	assert.Equal(t, []string{"dspo_restore_in_progress", "run_details"}, tables())
+
+	// The retry drops the tables of the failed import and restores the dump
+	require.NoError(t, restore("CREATE run_details\nCREATE pipelines\n"))
+	assert.Equal(t, []string{"run_details", "pipelines"}, tables())
+
+	// A restored database is not restored again
+	require.NoError(t, restore("FAIL\n"))
+	assert.Equal(t, []string{"run_details", "pipelines"}, tables())
+
+	// Nor is a database with existing data
+	require.NoError(t, os.WriteFile(tablesFile, []byte("experiments\n"), 0o644))
+	require.NoError(t, restore("CREATE run_details\n"))
+	assert.Equal(t, []string{"experiments"}, tables())
+}
@@ -18,10 +18,18 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 )
 
@@ -124,3 +132,66 @@ func TestDontDeployDatabase(t *testing.T) {
 	assert.False(t, created)
 	assert.Nil(t, err)
 }
+
+// postgreSQLConnectionAttempts records whether each connection to a PostgreSQL server without TLS starts with a TLS
+// or a plaintext startup message
+func postgreSQLConnectionAttempts(t *testing.T, sslMode string) []string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	var mu sync.Mutex
+	var attempts []string
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			header := make([]byte, 8)
+			if _, err := io.ReadFull(conn, header); err == nil {
+				mu.Lock()
+				switch binary.BigEndian.Uint32(header[4:]) {
+				case 80877103:
+					// SSLRequest, the server refuses TLS and closes the connection
+					attempts = append(attempts, "tls")
+					_, _ = conn.Write([]byte("N"))
+				case 80877102:
+					// CancelRequest of an abandoned attempt
+				default:
+					attempts = append(attempts, "plaintext")
+				}
+				mu.Unlock()
+			}
+			conn.Close()
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	pgConfig, err := healthCheckPostgreSQLConfig(host, port, "user", "password", "db", sslMode, nil, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = pgconn.ConnectConfig(ctx, &pgConfig.Config)
+	assert.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return attempts
+}
+
+func TestPostgreSQLHealthCheckSSLModes(t *testing.T) {
+	// Like libpq, "prefer" falls back to plaintext and "allow" falls back to TLS
+	tests := map[string][]string{
+		"disable": {"plaintext"},
+		"allow":   {"plaintext", "tls"},
+		"prefer":  {"tls", "plaintext"},
+		"require": {"tls"},
+	}
+	for sslMode, expected := range tests {
+		t.Run(sslMode, func(t *testing.T) {
+			assert.Equal(t, expected, postgreSQLConnectionAttempts(t, sslMode))
+		})
+	}
+}
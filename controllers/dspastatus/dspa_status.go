@@ -29,6 +29,10 @@ type DSPAStatus interface {
 	SetWorkflowControllerNotReady(err error, reason string)
 	SetWorkflowControllerReady()
 	SetWorkflowControllerNotApplicable()
+	SetWorkflowControllerExternal()
+
+	SetExternalWorkflowControllerCompatibleStatus(externalWorkflowControllerCompatible metav1.Condition)
+	SetExternalWorkflowControllerCompatibleNotApplicable()
 
 	SetMLMDProxyStatus(mlmdProxyReady metav1.Condition)
 
@@ -36,9 +40,28 @@ type DSPAStatus interface {
 	SetManagedPipelineInvalid(err error, reason string)
 	SetManagedPipelineNotApplicable()
 
+	SetDatabaseBackupHealthy(message string)
+	SetDatabaseBackupNotHealthy(err error, reason string)
+	SetDatabaseBackupNotApplicable()
+
+	SetManagedBucketInSync(message string)
+	SetManagedBucketNotInSync(err error, reason string)
+	SetManagedBucketNotApplicable()
+
+	SetBucketEncryptionInSync(message string)
+	SetBucketEncryptionNotInSync(err error, reason string)
+	SetBucketEncryptionNotApplicable()
+
 	SetDSPANotReady(err error, reason string)
 
+	SetObjectStorageStatus(objectStorage *dspav1.ObjectStorageStatus)
+	SetCommonMetadataStatus(commonMetadata *dspav1.CommonMetadataStatus)
+	AddCredentialRotations(rotations []dspav1.CredentialRotationRecord)
+
 	GetConditions() []metav1.Condition
+	GetObjectStorageStatus() *dspav1.ObjectStorageStatus
+	GetCommonMetadataStatus() *dspav1.CommonMetadataStatus
+	GetCredentialRotationStatus() *dspav1.CredentialRotationStatus
 }
 
 func NewDSPAStatus(dspa *dspav1.DataSciencePipelinesApplication) DSPAStatus {
@@ -51,6 +74,10 @@ func NewDSPAStatus(dspa *dspav1.DataSciencePipelinesApplication) DSPAStatus {
 	mlmdProxyReadyCondition := BuildUnknownCondition(config.MLMDProxyReady)
 	webhookReadyCondition := BuildUnknownCondition(config.WebhookReady)
 	managedPipelineValidCondition := BuildUnknownCondition(config.ManagedPipelineValid)
+	databaseBackupHealthyCondition := BuildUnknownCondition(config.DatabaseBackupHealthy)
+	managedBucketInSyncCondition := BuildUnknownCondition(config.ManagedBucketInSync)
+	bucketEncryptionInSyncCondition := BuildUnknownCondition(config.BucketEncryptionInSync)
+	externalWorkflowControllerCompatibleCondition := BuildUnknownCondition(config.ExternalWorkflowControllerCompatible)
 
 	return &dspaStatus{
 		dspa:                    dspa,
@@ -63,6 +90,14 @@ func NewDSPAStatus(dspa *dspav1.DataSciencePipelinesApplication) DSPAStatus {
 		mlmdProxyReady:          &mlmdProxyReadyCondition,
 		webhookReady:            &webhookReadyCondition,
 		managedPipelineValid:    &managedPipelineValidCondition,
+		databaseBackupHealthy:   &databaseBackupHealthyCondition,
+		managedBucketInSync:     &managedBucketInSyncCondition,
+		bucketEncryptionInSync:  &bucketEncryptionInSyncCondition,
+		objectStorage:           dspa.Status.ObjectStorage,
+		commonMetadata:          dspa.Status.CommonMetadata,
+		credentialRotation:      dspa.Status.CredentialRotation,
+
+		externalWorkflowControllerCompatible: &externalWorkflowControllerCompatibleCondition,
 	}
 }
 
@@ -78,6 +113,14 @@ type dspaStatus struct {
 	dspaReady               *metav1.Condition
 	webhookReady            *metav1.Condition
 	managedPipelineValid    *metav1.Condition
+	databaseBackupHealthy   *metav1.Condition
+	managedBucketInSync     *metav1.Condition
+	bucketEncryptionInSync  *metav1.Condition
+	objectStorage           *dspav1.ObjectStorageStatus
+	commonMetadata          *dspav1.CommonMetadataStatus
+	credentialRotation      *dspav1.CredentialRotationStatus
+
+	externalWorkflowControllerCompatible *metav1.Condition
 }
 
 func (s *dspaStatus) SetDatabaseNotReady(err error, reason string) {
@@ -163,6 +206,20 @@ func (s *dspaStatus) SetWorkflowControllerNotApplicable() {
 	s.workflowControllerReady = &condition
 }
 
+func (s *dspaStatus) SetWorkflowControllerExternal() {
+	condition := BuildFalseCondition(config.WorkflowControllerReady, "NotApplicable", "WorkflowController is provided by an external Argo Workflows installation")
+	s.workflowControllerReady = &condition
+}
+
+func (s *dspaStatus) SetExternalWorkflowControllerCompatibleStatus(externalWorkflowControllerCompatible metav1.Condition) {
+	s.externalWorkflowControllerCompatible = &externalWorkflowControllerCompatible
+}
+
+func (s *dspaStatus) SetExternalWorkflowControllerCompatibleNotApplicable() {
+	condition := BuildFalseCondition(config.ExternalWorkflowControllerCompatible, "NotApplicable", "WorkflowController is not in External mode")
+	s.externalWorkflowControllerCompatible = &condition
+}
+
 func (s *dspaStatus) SetMLMDProxyStatus(mlmdProxyReady metav1.Condition) {
 	s.mlmdProxyReady = &mlmdProxyReady
 }
@@ -186,6 +243,63 @@ func (s *dspaStatus) SetManagedPipelineNotApplicable() {
 	s.managedPipelineValid = &condition
 }
 
+func (s *dspaStatus) SetDatabaseBackupHealthy(message string) {
+	condition := BuildTrueCondition(config.DatabaseBackupHealthy, message)
+	s.databaseBackupHealthy = &condition
+}
+
+func (s *dspaStatus) SetDatabaseBackupNotHealthy(err error, reason string) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	condition := BuildFalseCondition(config.DatabaseBackupHealthy, reason, message)
+	s.databaseBackupHealthy = &condition
+}
+
+func (s *dspaStatus) SetDatabaseBackupNotApplicable() {
+	condition := BuildFalseCondition(config.DatabaseBackupHealthy, "NotApplicable", "Scheduled database backups are not configured")
+	s.databaseBackupHealthy = &condition
+}
+
+func (s *dspaStatus) SetManagedBucketInSync(message string) {
+	condition := BuildTrueCondition(config.ManagedBucketInSync, message)
+	s.managedBucketInSync = &condition
+}
+
+func (s *dspaStatus) SetManagedBucketNotInSync(err error, reason string) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	condition := BuildFalseCondition(config.ManagedBucketInSync, reason, message)
+	s.managedBucketInSync = &condition
+}
+
+func (s *dspaStatus) SetManagedBucketNotApplicable() {
+	condition := BuildFalseCondition(config.ManagedBucketInSync, "NotApplicable", "Bucket management is not configured")
+	s.managedBucketInSync = &condition
+}
+
+func (s *dspaStatus) SetBucketEncryptionInSync(message string) {
+	condition := BuildTrueCondition(config.BucketEncryptionInSync, message)
+	s.bucketEncryptionInSync = &condition
+}
+
+func (s *dspaStatus) SetBucketEncryptionNotInSync(err error, reason string) {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+	condition := BuildFalseCondition(config.BucketEncryptionInSync, reason, message)
+	s.bucketEncryptionInSync = &condition
+}
+
+func (s *dspaStatus) SetBucketEncryptionNotApplicable() {
+	condition := BuildFalseCondition(config.BucketEncryptionInSync, "NotApplicable", "Server-side encryption is not configured")
+	s.bucketEncryptionInSync = &condition
+}
+
 // SetDSPANotReady is an override option for reporting a custom
 // overall DSP Ready state. This is the condition type that
 // reports on the overall state of the DSPA. If this is never
@@ -200,6 +314,44 @@ func (s *dspaStatus) SetDSPANotReady(err error, reason string) {
 	s.dspaReady = &condition
 }
 
+// SetObjectStorageStatus reports the availability of the additionalStores. It is kept from the previous
+// reconciliation until the object storage health check runs again.
+func (s *dspaStatus) SetObjectStorageStatus(objectStorage *dspav1.ObjectStorageStatus) {
+	s.objectStorage = objectStorage
+}
+
+func (s *dspaStatus) GetObjectStorageStatus() *dspav1.ObjectStorageStatus {
+	return s.objectStorage
+}
+
+// SetCommonMetadataStatus records the keys of the common labels and annotations applied to the DSPA resources. It is
+// kept from the previous reconciliation until the parameters of the DSPA are extracted again.
+func (s *dspaStatus) SetCommonMetadataStatus(commonMetadata *dspav1.CommonMetadataStatus) {
+	s.commonMetadata = commonMetadata
+}
+
+func (s *dspaStatus) GetCommonMetadataStatus() *dspav1.CommonMetadataStatus {
+	return s.commonMetadata
+}
+
+// AddCredentialRotations prepends rotations to the rotation history of the DSPA, which keeps the most recent ones.
+func (s *dspaStatus) AddCredentialRotations(rotations []dspav1.CredentialRotationRecord) {
+	credentialRotation := &dspav1.CredentialRotationStatus{}
+	if s.credentialRotation != nil {
+		credentialRotation = s.credentialRotation.DeepCopy()
+	}
+	history := append(append([]dspav1.CredentialRotationRecord{}, rotations...), credentialRotation.History...)
+	if len(history) > config.CredentialRotationHistoryLimit {
+		history = history[:config.CredentialRotationHistoryLimit]
+	}
+	credentialRotation.History = history
+	s.credentialRotation = credentialRotation
+}
+
+func (s *dspaStatus) GetCredentialRotationStatus() *dspav1.CredentialRotationStatus {
+	return s.credentialRotation
+}
+
 func (s *dspaStatus) GetConditions() []metav1.Condition {
 	componentConditions := []metav1.Condition{
 		*s.getDatabaseAvailableCondition(),
@@ -211,6 +363,7 @@ func (s *dspaStatus) GetConditions() []metav1.Condition {
 		*s.getMLMDProxyReadyCondition(),
 		*s.getWebhookReadyCondition(),
 		*s.getManagedPipelineValidCondition(),
+		*s.getExternalWorkflowControllerCompatibleCondition(),
 	}
 
 	allReady := true
@@ -257,6 +410,11 @@ func (s *dspaStatus) GetConditions() []metav1.Condition {
 		*s.mlmdProxyReady,
 		*s.webhookReady,
 		*s.managedPipelineValid,
+		*s.externalWorkflowControllerCompatible,
+		// Backup health, bucket management and bucket encryption are reported, but do not gate the overall ready state
+		*s.databaseBackupHealthy,
+		*s.managedBucketInSync,
+		*s.bucketEncryptionInSync,
 		*crReady,
 	}
 
@@ -310,6 +468,10 @@ func (s *dspaStatus) getManagedPipelineValidCondition() *metav1.Condition {
 	return s.managedPipelineValid
 }
 
+func (s *dspaStatus) getExternalWorkflowControllerCompatibleCondition() *metav1.Condition {
+	return s.externalWorkflowControllerCompatible
+}
+
 func BuildTrueCondition(conditionType string, message string) metav1.Condition {
 	condition := metav1.Condition{}
 	condition.Type = conditionType
@@ -345,7 +507,10 @@ func BuildUnknownCondition(conditionType string) metav1.Condition {
 // isNonBlockingReason returns true for condition reasons that should not
 // degrade the overall CrReady status. "NotApplicable" means the feature is
 // not configured; "ManagedPipelinesFetchError" means a transient fetch
-// failure occurred but the controller allows deployment to proceed.
+// failure occurred but the controller allows deployment to proceed;
+// "ArgoWorkflowsVersionUnknown" means an external Argo Workflows installation
+// was found but its version could not be verified.
 func isNonBlockingReason(reason string) bool {
-	return reason == "NotApplicable" || reason == config.ManagedPipelinesFetchError
+	return reason == "NotApplicable" || reason == config.ManagedPipelinesFetchError ||
+		reason == config.ArgoWorkflowsVersionUnknown
 }
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -37,8 +39,12 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -79,6 +85,10 @@ type DSPAReconciler struct {
 	MLflowEndpointCacheTTL time.Duration
 	mlflowEndpointCache    map[string]mlflowEndpointCacheEntry
 	mlflowEndpointCacheMu  sync.RWMutex
+	// ClusterCapabilities select how the components are exposed outside of the cluster
+	ClusterCapabilities ClusterCapabilities
+	// TLSProfile is the TLS security profile of the cluster, the Intermediate profile is used when it is not set
+	TLSProfile *TLSProfile
 }
 
 type mlflowEndpointCacheEntry struct {
@@ -158,17 +168,30 @@ func (r *DSPAReconciler) retrieveMLflowEndpointCached(ctx context.Context, names
 	return endpoint, nil
 }
 
+// getServiceUncached reads a Service directly from the API server, the cache only holds the Services of the operator
+func (r *DSPAReconciler) getServiceUncached(ctx context.Context, namespacedName types.NamespacedName) (*corev1.Service, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	service := &corev1.Service{}
+	if err := reader.Get(ctx, namespacedName, service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
 func (r *DSPAReconciler) ApplyDir(owner mf.Owner, params *DSPAParams, directory string, fns ...mf.Transformer) error {
 	templates, err := util.GetTemplatesInDir(r.TemplatesPath, directory)
 	if err != nil {
 		return err
 	}
-	return r.ApplyAll(owner, params, templates)
+	return r.ApplyAll(owner, params, templates, fns...)
 }
 
 func (r *DSPAReconciler) ApplyAll(owner mf.Owner, params *DSPAParams, templates []string, fns ...mf.Transformer) error {
 	for _, template := range templates {
-		err := r.Apply(owner, params, template)
+		err := r.Apply(owner, params, template, fns...)
 		if err != nil {
 			return err
 		}
@@ -188,11 +211,23 @@ func (r *DSPAReconciler) Apply(owner mf.Owner, params *DSPAParams, template stri
 		// Apply dsp-version=<ver> label on all resources managed by this dspo
 		util.AddLabelTransformer(config.DSPVersionk8sLabel, params.DSPVersion),
 		util.AddDeploymentPodLabelTransformer(config.DSPVersionk8sLabel, params.DSPVersion),
+		podTemplateOverridesTransformer(params.PodTemplateOverrides),
 	)
 	if err != nil {
 		return err
 	}
 
+	// The common labels and annotations of a DSPA are not applied to the resources the operator shares between DSPAs
+	if _, ok := owner.(*dspav1.DataSciencePipelinesApplication); ok {
+		tmplManifest, err = tmplManifest.Transform(
+			util.AddCommonMetadataTransformer(params.CommonLabels, params.CommonAnnotations),
+			util.AddDeploymentPodMetadataTransformer(params.PodLabels, params.PodAnnotations),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Apply dsp-version labels to all manifests
 	tmplManifest, err = tmplManifest.Transform(fns...)
 	if err != nil {
@@ -270,24 +305,32 @@ func (r *DSPAReconciler) DeleteResourceIfItExists(ctx context.Context, obj clien
 //+kubebuilder:rbac:groups=datasciencepipelinesapplications.opendatahub.io,resources=datasciencepipelinesapplications/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=*,resources=deployments;services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=secrets;configmaps;services;serviceaccounts;persistentvolumes;persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=persistentvolumes;persistentvolumeclaims,verbs=*
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts/token,verbs=create
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=create;delete;get
 //+kubebuilder:rbac:groups=argoproj.io,resources=workflows,verbs=*
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
 //+kubebuilder:rbac:groups=argoproj.io,resources=workflowtaskresults,verbs=create;patch
 //+kubebuilder:rbac:groups=argoproj.io,resources=workflowartifactgctasks;workflowartifactgctasks/finalizers,verbs=*
 //+kubebuilder:rbac:groups=core,resources=pods;pods/exec;pods/log;services,verbs=*
 //+kubebuilder:rbac:groups=core;apps;extensions,resources=deployments;deployments/finalizers;replicasets,verbs=*
 //+kubebuilder:rbac:groups=kubeflow.org,resources=*,verbs=*
-//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=*
 //+kubebuilder:rbac:groups=machinelearning.seldon.io,resources=seldondeployments,verbs=*
 //+kubebuilder:rbac:groups=ray.io,resources=rayclusters;rayjobs;rayservices,verbs=create;get;list;patch;delete
 //+kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 //+kubebuilder:rbac:groups=serving.kserve.io,resources=inferenceservices,verbs=create;get;list;patch;delete
 //+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 //+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
@@ -310,6 +353,8 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		WebhookAnnotations: r.WebhookAnnotations,
 	}
 	params.ResolveMLflowEndpoint = r.retrieveMLflowEndpointCached
+	params.GetService = r.getServiceUncached
+	params.SetupTLSProfile(r.TLSProfile.Get())
 
 	dspa := &dspav1.DataSciencePipelinesApplication{}
 	err := r.Get(ctx, req.NamespacedName, dspa)
@@ -338,6 +383,7 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			util.GetConditionByType(config.WorkflowControllerReady, conditions): WorkflowControllerReadyMetric,
 			util.GetConditionByType(config.MLMDProxyReady, conditions):          MLMDProxyReadyMetric,
 			util.GetConditionByType(config.ManagedPipelineValid, conditions):    ManagedPipelineValidMetric,
+			util.GetConditionByType(config.DatabaseBackupHealthy, conditions):   DatabaseBackupHealthyMetric,
 			util.GetConditionByType(config.CrReady, conditions):                 CrReadyMetric,
 		}
 		r.PublishMetrics(dspa, metricsMap)
@@ -411,6 +457,16 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		dspaStatus.SetDSPANotReady(err, config.FailingToDeploy)
 		return ctrl.Result{Requeue: true, RequeueAfter: requeueTime}, nil
 	}
+	// Keys removed from the common labels and annotations stay in the status until all the resources are applied
+	dspaStatus.SetCommonMetadataStatus(commonMetadataStatus(dspa.Status.CommonMetadata, params, false))
+
+	// Rotated credentials must be in place before the secrets and the deployments consuming them are applied
+	err = r.ReconcileCredentialRotation(ctx, dspa, params, dspaStatus)
+	if err != nil {
+		log.Error(err, "Failed to rotate generated credentials")
+		dspaStatus.SetDSPANotReady(err, config.CredentialRotationFailed)
+		return ctrl.Result{}, err
+	}
 
 	err = r.ReconcileDatabase(ctx, dspa, params)
 	if err != nil {
@@ -428,6 +484,45 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		dspaStatus.SetObjStoreReady()
 	}
 
+	// The managed bucket is created before the object storage health check below
+	bucketManaged, corrected, err := r.ReconcileManagedBucket(ctx, dspa, params)
+	if err != nil {
+		dspaStatus.SetManagedBucketNotInSync(err, objectStoreHealthCheckReason(err))
+	} else if !bucketManaged {
+		dspaStatus.SetManagedBucketNotApplicable()
+	} else if len(corrected) > 0 {
+		dspaStatus.SetManagedBucketInSync("Corrected managed bucket configuration: " + strings.Join(corrected, "; "))
+	} else {
+		dspaStatus.SetManagedBucketInSync("Managed bucket configuration is in sync")
+	}
+
+	// The KFP API server and launcher rely on the default encryption of the bucket
+	encrypted, encryptionCorrected, err := r.ReconcileBucketEncryption(ctx, dspa, params)
+	if err != nil {
+		dspaStatus.SetBucketEncryptionNotInSync(err, objectStoreHealthCheckReason(err))
+	} else if !encrypted {
+		dspaStatus.SetBucketEncryptionNotApplicable()
+	} else if encryptionCorrected != "" {
+		dspaStatus.SetBucketEncryptionInSync("Corrected bucket encryption: " + encryptionCorrected)
+	} else {
+		dspaStatus.SetBucketEncryptionInSync("Bucket default encryption matches the configured server-side encryption")
+	}
+
+	// Backups depend on both the database and object storage connection details resolved above
+	backupEnabled, err := r.ReconcileDatabaseBackup(ctx, dspa, params)
+	if err != nil {
+		dspaStatus.SetDatabaseBackupNotHealthy(err, config.FailingToDeploy)
+	} else if !backupEnabled {
+		dspaStatus.SetDatabaseBackupNotApplicable()
+	} else {
+		healthy, message := r.checkDatabaseBackupStatus(ctx, dspa)
+		if healthy {
+			dspaStatus.SetDatabaseBackupHealthy(message)
+		} else {
+			dspaStatus.SetDatabaseBackupNotHealthy(errors.New(message), config.DatabaseBackupFailed)
+		}
+	}
+
 	// Get Prereq Status (DB and ObjStore Ready)
 	dbAvailable, err := r.isDatabaseAccessible(dspa, params)
 
@@ -439,17 +534,18 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	objStoreAvailable, err := r.isObjectStorageAccessible(ctx, dspa, params)
 	if err != nil {
-		dspaStatus.SetObjStoreNotReady(err, config.FailingToDeploy)
+		dspaStatus.SetObjStoreNotReady(err, objectStoreHealthCheckReason(err))
 	} else {
 		dspaStatus.SetObjStoreReady()
 	}
+	dspaStatus.SetObjectStorageStatus(r.checkAdditionalObjectStores(ctx, dspa, params))
 
 	dspaPrereqsReady := dbAvailable && objStoreAvailable
 	managedPipelinesRequeue := false
 
 	if dspaPrereqsReady {
 		// Manage Common Manifests
-		err = r.ReconcileCommon(dspa, params)
+		err = r.ReconcileCommon(ctx, dspa, params)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -483,6 +579,15 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			return ctrl.Result{}, nil
 		}
 
+		// The API Server must not start against an empty database while a restore is pending
+		restored, reason, message := r.checkDatabaseRestoreStatus(ctx, dspa, params)
+		if !restored {
+			log.Info(message)
+			r.preservePostValidationConditions(dspa, dspaStatus)
+			dspaStatus.SetApiServerStatus(dspastatus.BuildFalseCondition(config.APIServerReady, reason, message))
+			return ctrl.Result{RequeueAfter: requeueTime}, nil
+		}
+
 		err = r.ReconcileAPIServer(ctx, dspa, params)
 		if err != nil {
 			r.setStatusAsNotReady(config.APIServerReady, err, dspaStatus.SetApiServerStatus)
@@ -510,19 +615,36 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 				dspaStatus.SetScheduledWorkflowStatus, log)
 		}
 
-		workflowControllerEnabled, err := r.ReconcileWorkflowController(dspa, params)
-		if err != nil {
+		workflowControllerEnabled, err := r.ReconcileWorkflowController(ctx, dspa, params)
+		var invalidConfigErr *InvalidWorkflowControllerConfigError
+		if errors.As(err, &invalidConfigErr) {
+			// The configuration is only fixed by the user, the DSPA is reconciled again when it changes
+			log.Info(fmt.Sprintf("Skipping the WorkflowController deployment: %s", err))
+			dspaStatus.SetWorkflowControllerNotReady(err, config.InvalidWorkflowConfig)
+		} else if err != nil {
 			dspaStatus.SetWorkflowControllerNotReady(err, config.FailingToDeploy)
 			return ctrl.Result{}, err
 		} else {
 			if workflowControllerEnabled {
 				r.setStatus(ctx, params.WorkflowControllerDefaultResourceName, config.WorkflowControllerReady, dspa,
 					dspaStatus.SetWorkflowControllerStatus, log)
+			} else if usesExternalWorkflowController(dspa) {
+				dspaStatus.SetWorkflowControllerExternal()
 			} else {
 				dspaStatus.SetWorkflowControllerNotApplicable()
 			}
 		}
 
+		if usesExternalWorkflowController(dspa) {
+			externalWorkflowControllerCompatible, err := r.CheckExternalWorkflowController(ctx, dspa, params)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			dspaStatus.SetExternalWorkflowControllerCompatibleStatus(externalWorkflowControllerCompatible)
+		} else {
+			dspaStatus.SetExternalWorkflowControllerCompatibleNotApplicable()
+		}
+
 		// MLMD should be the last to reconcile because it can cause an early exit due to the lack of the TLS secret, which may not have been created yet.
 		err = r.ReconcileMLMD(ctx, dspa, params)
 		if err != nil {
@@ -532,6 +654,8 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			r.setStatus(ctx, params.MlmdProxyDefaultResourceName, config.MLMDProxyReady, dspa,
 				dspaStatus.SetMLMDProxyStatus, log)
 		}
+
+		dspaStatus.SetCommonMetadataStatus(commonMetadataStatus(dspa.Status.CommonMetadata, params, true))
 	}
 
 	if !dspaPrereqsReady {
@@ -543,6 +667,9 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if managedPipelinesRequeue {
 		return ctrl.Result{RequeueAfter: requeueTime}, nil
 	}
+	if params.ObjectStorageCredentialRotationDeferred {
+		return ctrl.Result{RequeueAfter: config.CredentialRotationDeferredRequeueTime}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -563,6 +690,7 @@ func (r *DSPAReconciler) preservePostValidationConditions(
 		{config.PersistenceAgentReady, dspaStatus.SetPersistenceAgentStatus},
 		{config.ScheduledWorkflowReady, dspaStatus.SetScheduledWorkflowStatus},
 		{config.WorkflowControllerReady, dspaStatus.SetWorkflowControllerStatus},
+		{config.ExternalWorkflowControllerCompatible, dspaStatus.SetExternalWorkflowControllerCompatibleStatus},
 		{config.MLMDProxyReady, dspaStatus.SetMLMDProxyStatus},
 	}
 	for _, cs := range conditionSetters {
@@ -683,6 +811,10 @@ func (r *DSPAReconciler) updateStatus(ctx context.Context, dspa *dspav1.DataScie
 		return
 	}
 	dspa.Status.Components = r.GetComponents(ctx, dspa)
+	dspa.Status.DatabaseBackup = r.GetDatabaseBackupStatus(ctx, dspa)
+	dspa.Status.ObjectStorage = dspaStatus.GetObjectStorageStatus()
+	dspa.Status.CommonMetadata = dspaStatus.GetCommonMetadataStatus()
+	dspa.Status.CredentialRotation = dspaStatus.GetCredentialRotationStatus()
 	dspa.Status.Conditions = dspaStatus.GetConditions()
 	err := r.Status().Update(ctx, dspa)
 	if err != nil {
@@ -690,6 +822,20 @@ func (r *DSPAReconciler) updateStatus(ctx context.Context, dspa *dspav1.DataScie
 	}
 }
 
+// guaranteedReplicas returns the number of replicas the PodDisruptionBudget keeps available for a deployment running
+// several replicas. The minReplicas of its HorizontalPodAutoscaler is the baseline when it is autoscaled.
+func (r *DSPAReconciler) guaranteedReplicas(ctx context.Context, deployment *appsv1.Deployment) (int32, error) {
+	replicas := *deployment.Spec.Replicas
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, hpa)
+	if err == nil && hpa.Spec.MinReplicas != nil {
+		replicas = min(replicas, *hpa.Spec.MinReplicas)
+	} else if err != nil && !apierrs.IsNotFound(err) {
+		return 0, err
+	}
+	return max(1, replicas-config.APIServerPodDisruptionBudgetMaxUnavailable), nil
+}
+
 // evaluateCondition evaluates if condition with "name" is in condition of type "conditionType".
 // this procedure is valid only for conditions with bool status type, for conditions of non bool type
 // results are undefined.
@@ -727,6 +873,27 @@ func (r *DSPAReconciler) evaluateCondition(ctx context.Context, dspa *dspav1.Dat
 	replicaFailureCond := util.GetDeploymentCondition(deployment.Status, appsv1.DeploymentReplicaFailure)
 
 	if availableCond != nil && availableCond.Status == corev1.ConditionTrue {
+		// Components running several replicas, e.g. a highly available API Server, stay ready while the replicas
+		// guaranteed by their PodDisruptionBudget are available, so that scale ups, rolling updates and node drains
+		// do not flap readiness. A shortfall of the desired replicas is reported in the message.
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 1 && deployment.Status.AvailableReplicas < *deployment.Spec.Replicas {
+			guaranteedReplicas, err := r.guaranteedReplicas(ctx, deployment)
+			if err != nil {
+				return metav1.Condition{}, err
+			}
+			if deployment.Status.AvailableReplicas < guaranteedReplicas {
+				condition.Reason = config.DesiredReplicasUnavailable
+				condition.Status = metav1.ConditionFalse
+				condition.Message = fmt.Sprintf("Component [%s] has %d of %d desired replicas available, at least %d are required.",
+					component, deployment.Status.AvailableReplicas, *deployment.Spec.Replicas, guaranteedReplicas)
+				return condition, nil
+			}
+			condition.Reason = config.MinimumReplicasAvailable
+			condition.Status = metav1.ConditionTrue
+			condition.Message = fmt.Sprintf("Component [%s] is minimally available with %d of %d desired replicas.", component,
+				deployment.Status.AvailableReplicas, *deployment.Spec.Replicas)
+			return condition, nil
+		}
 		// If this DSPA component is minimally available, we are done.
 		condition.Reason = config.MinimumReplicasAvailable
 		condition.Status = metav1.ConditionTrue
@@ -843,9 +1010,9 @@ func (r *DSPAReconciler) GetComponents(ctx context.Context, dspa *dspav1.DataSci
 		log.Error(err, "Error retrieving MLMD Proxy Service endpoint")
 	}
 
-	mlmdProxyExternalUrl, err := util.GetRouteHostname(ctx, mlmdProxyResourceName, dspa.Namespace, r.Client)
+	mlmdProxyExternalUrl, err := r.getExternalUrl(ctx, mlmdProxyResourceName, dspa.Namespace)
 	if err != nil {
-		log.Error(err, "Error retrieving MLMD Proxy Route endpoint")
+		log.Error(err, "Error retrieving MLMD Proxy external endpoint")
 	}
 
 	apiServerUrl, err := util.GetServiceHostname(ctx, apiServerResourceName, dspa.Namespace, r.Client)
@@ -853,9 +1020,9 @@ func (r *DSPAReconciler) GetComponents(ctx context.Context, dspa *dspav1.DataSci
 		log.Error(err, "Error retrieving API Server Service endpoint")
 	}
 
-	apiServerExternalUrl, err := util.GetRouteHostname(ctx, apiServerResourceName, dspa.Namespace, r.Client)
+	apiServerExternalUrl, err := r.getExternalUrl(ctx, apiServerResourceName, dspa.Namespace)
 	if err != nil {
-		log.Error(err, "Error retrieving API Server Route endpoint")
+		log.Error(err, "Error retrieving API Server external endpoint")
 	}
 
 	mlmdProxyComponent := &dspav1.ComponentDetailStatus{}
@@ -889,9 +1056,11 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.ManifestFetcher == nil {
 		r.ManifestFetcher = NewOCIManifestFetcher(r.Log, r.AllowedRegistries)
 	}
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&dspav1.DataSciencePipelinesApplication{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
@@ -900,28 +1069,58 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&networkingv1.NetworkPolicy{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
-		Owns(&routev1.Route{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{})
+	// The APIs used to expose the components and issue their certificates are optional, only watch the ones served
+	// by the cluster
+	if r.ClusterCapabilities.Routes {
+		controllerBuilder = controllerBuilder.Owns(&routev1.Route{})
+	}
+	if r.ClusterCapabilities.Ingresses {
+		controllerBuilder = controllerBuilder.Owns(&networkingv1.Ingress{})
+	}
+	if r.ClusterCapabilities.HTTPRoutes {
+		controllerBuilder = controllerBuilder.Owns(newHTTPRoute())
+	}
+	if r.ClusterCapabilities.Certificates {
+		controllerBuilder = controllerBuilder.Owns(newCertificate())
+	}
+	// The DSPAs using an external Argo Workflows installation are reconciled when its workflow controller changes
+	controllerBuilder = controllerBuilder.Watches(&appsv1.Deployment{},
+		handler.EnqueueRequestsFromMapFunc(r.externalWorkflowControllerReconcileRequests))
+	// The DSPAs restricting the egress traffic are reconciled when the Kubernetes API server endpoints change
+	controllerBuilder = controllerBuilder.Watches(&discoveryv1.EndpointSlice{},
+		handler.EnqueueRequestsFromMapFunc(r.kubernetesAPIServerEndpointsReconcileRequests))
+	// Every DSPA is reconciled when the TLS security profile of the cluster changes
+	if r.TLSProfile != nil {
+		controllerBuilder = controllerBuilder.WatchesRawSource(source.Channel(r.TLSProfile.events,
+			&handler.EnqueueRequestForObject{}))
+	}
+	return controllerBuilder.
 		// Watch for global ca bundle, if one is added to this namespace
 		// we need to reconcile on all the dspa's in this namespace
-		// so they may mount this cert in the appropriate containers
+		// so they may mount this cert in the appropriate containers.
+		// The DSPAs merging a custom workflow controller configuration or
+		// trusting the CA bundle of a ConfigMap are reconciled as well when
+		// that ConfigMap changes.
 		WatchesRawSource(source.Kind[client.Object](mgr.GetCache(), &corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 				cm := o.(*corev1.ConfigMap)
 				thisNamespace := cm.Namespace
 				log := r.Log.WithValues("namespace", thisNamespace)
 
-				if cm.Name != "odh-trusted-ca-bundle" {
-					return nil
-				}
-
 				var dspaList dspav1.DataSciencePipelinesApplicationList
 				if err := r.List(ctx, &dspaList, client.InNamespace(thisNamespace)); err != nil {
-					log.Error(err, "unable to list DSPA's when attempting to handle Global CA Bundle event.")
+					log.Error(err, "unable to list DSPA's when attempting to handle ConfigMap event.")
 					return nil
 				}
 
+				isGlobalCABundle := cm.Name == "odh-trusted-ca-bundle"
 				var reconcileRequests []reconcile.Request
 				for _, dspa := range dspaList.Items {
+					if !isGlobalCABundle && !usesWorkflowControllerCustomConfig(&dspa, cm.Name) && !usesCABundleConfigMap(&dspa, cm.Name) {
+						continue
+					}
 					// Only update supported DSP versions
 					if util.DSPAWithSupportedDSPVersion(&dspa) {
 						namespacedName := types.NamespacedName{
@@ -933,7 +1132,7 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				}
 
 				if len(reconcileRequests) > 0 {
-					log.V(1).Info(fmt.Sprintf("Reconcile event triggered by change in event on Global CA Bundle: %s", cm.Name))
+					log.V(1).Info(fmt.Sprintf("Reconcile event triggered by change in event on ConfigMap: %s", cm.Name))
 				}
 
 				return reconcileRequests
@@ -977,7 +1176,8 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				log := r.Log.WithValues("namespace", secret.Namespace)
 
 				if secret.Annotations["openshift.io/owning-component"] != "service-ca" {
-					return nil
+					requests := append(certManagerSecretReconcileRequests(secret), r.dspasWithRouteCertificateSecret(ctx, secret)...)
+					return append(requests, r.dspasReferencingSecret(ctx, secret)...)
 				}
 				serviceName := secret.Annotations["service.beta.openshift.io/originating-service-name"]
 
@@ -1017,6 +1217,73 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// usesCABundleConfigMap returns whether the components of the DSPA trust the CA bundle of the ConfigMap
+func usesCABundleConfigMap(dsp *dspav1.DataSciencePipelinesApplication, configMapName string) bool {
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.CABundle != nil && dsp.Spec.APIServer.CABundle.ConfigMapName == configMapName {
+		return true
+	}
+	return configMapName == config.OpenshiftServiceCAConfigMapName && (dsp.Spec.PodToPodTLS == nil || *dsp.Spec.PodToPodTLS)
+}
+
+// referencedSecretNames returns the names of the user provided Secrets holding the credentials of the DSPA
+func referencedSecretNames(dsp *dspav1.DataSciencePipelinesApplication) []string {
+	var secretNames []string
+	if database := dsp.Spec.Database; database != nil {
+		if database.ExternalDB != nil && database.ExternalDB.PasswordSecret != nil {
+			secretNames = append(secretNames, database.ExternalDB.PasswordSecret.Name)
+		}
+		if database.MariaDB != nil && database.MariaDB.PasswordSecret != nil {
+			secretNames = append(secretNames, database.MariaDB.PasswordSecret.Name)
+		}
+	}
+	objectStorage := dsp.Spec.ObjectStorage
+	if objectStorage == nil {
+		return secretNames
+	}
+	if externalStorage := objectStorage.ExternalStorage; externalStorage != nil {
+		if externalStorage.S3CredentialSecret != nil {
+			secretNames = append(secretNames, externalStorage.S3CredentialSecret.SecretName)
+		}
+		if externalStorage.GCSCredentialSecret != nil {
+			secretNames = append(secretNames, externalStorage.GCSCredentialSecret.SecretName)
+		}
+		if externalStorage.AzureCredentialSecret != nil {
+			secretNames = append(secretNames, externalStorage.AzureCredentialSecret.SecretName)
+		}
+	}
+	for _, store := range objectStorage.AdditionalStores {
+		if store.S3CredentialSecret != nil {
+			secretNames = append(secretNames, store.S3CredentialSecret.SecretName)
+		}
+	}
+	return secretNames
+}
+
+// dspasReferencingSecret returns the DSPAs whose credentials are held by the Secret, the components consuming them
+// are rolled through their content hashes so the DSPAs must be reconciled when the Secret changes
+func (r *DSPAReconciler) dspasReferencingSecret(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+	log := r.Log.WithValues("namespace", secret.Namespace)
+
+	var dspaList dspav1.DataSciencePipelinesApplicationList
+	if err := r.List(ctx, &dspaList, client.InNamespace(secret.Namespace)); err != nil {
+		log.Error(err, "unable to list DSPA's when attempting to handle Secret event.")
+		return nil
+	}
+
+	var reconcileRequests []reconcile.Request
+	for _, dspa := range dspaList.Items {
+		if !util.DSPAWithSupportedDSPVersion(&dspa) {
+			continue
+		}
+		if slices.Contains(referencedSecretNames(&dspa), secret.Name) {
+			namespacedName := types.NamespacedName{Name: dspa.Name, Namespace: dspa.Namespace}
+			reconcileRequests = append(reconcileRequests, reconcile.Request{NamespacedName: namespacedName})
+			log.V(1).Info(fmt.Sprintf("Reconcile event triggered by change on credentials Secret: %s", secret.Name))
+		}
+	}
+	return reconcileRequests
+}
+
 // cleanUpResources cleans up any resources not handled by garbage collection, like Cluster ResourceRequirements.
 func (r *DSPAReconciler) cleanUpResources(params *DSPAParams) error {
 	DeleteMetrics(params.Name, params.Namespace)
@@ -60,6 +60,10 @@ func NewFakeController() *DSPAReconciler {
 		Log:           ctrl.Log.WithName("controllers").WithName("ds-pipelines-controller"),
 		Scheme:        FakeScheme,
 		TemplatesPath: "../config/internal/",
+		ClusterCapabilities: ClusterCapabilities{
+			Routes:    true,
+			Ingresses: true,
+		},
 	}
 
 	return r
@@ -69,7 +73,9 @@ func CreateNewTestObjects() (context.Context, *DSPAParams, *DSPAReconciler) {
 	reconciler := NewFakeController()
 	params := &DSPAParams{
 		ResolveMLflowEndpoint: reconciler.retrieveMLflowEndpointCached,
+		GetService:            reconciler.getServiceUncached,
 	}
+	params.SetupTLSProfile(reconciler.TLSProfile.Get())
 	return context.Background(), params, reconciler
 }
 
@@ -19,6 +19,7 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -26,6 +27,7 @@ import (
 	"math/rand"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -42,6 +44,7 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -81,9 +84,30 @@ type DSPAParams struct {
 	WorkflowController                    *dspa.WorkflowController
 	CustomKfpLauncherConfigMapData        string
 	APIServerWorkspaceJSON                string
-	WebhookAnnotations                    map[string]string
+	// APIServerReplicas is 0 when the API Server is scaled by its HorizontalPodAutoscaler
+	APIServerReplicas int32
+	// APIServerHighlyAvailable is set when the API Server runs more than one replica, which are protected by a
+	// PodDisruptionBudget and spread across nodes
+	APIServerHighlyAvailable bool
+	APIServerAutoscaling     *dspa.APIServerAutoscaling
+	WebhookAnnotations       map[string]string
 	DBConnection
 	ObjectStorageConnection
+	// PodPlacement of each component, resolved from spec.podPlacement and the podPlacement of the component
+	PodPlacement ComponentPodPlacements
+	// Labels and annotations added to every resource of the DSPA, and to the pods of its components
+	CommonLabels      map[string]string
+	CommonAnnotations map[string]string
+	PodLabels         map[string]string
+	PodAnnotations    map[string]string
+	// Exposure of the components outside of the cluster, resolved when the components are reconciled
+	APIServerExposure ExposureParams
+	MlmdEnvoyExposure ExposureParams
+	MinioExposure     ExposureParams
+	// RouteCertificateSecretNames are the certificate Secrets the router reads for the Routes of the DSPA
+	RouteCertificateSecretNames []string
+	// PodTemplateOverrides of the components, by the name of their Deployment
+	PodTemplateOverrides map[string]*dspa.PodTemplateOverrides
 
 	// TLS
 	// The CA bundle path used by API server
@@ -104,6 +128,13 @@ type DSPAParams struct {
 	DSPONamespace  string
 	// Use to enable tls communication between component pods.
 	PodToPodTLS bool
+	// CertManager is set when the certificates of the components are issued by cert-manager instead of the
+	// service-ca operator
+	CertManager *dspa.CertManagerConfig
+	// NetworkPolicy holds the additional rules of the NetworkPolicies of the components
+	NetworkPolicy NetworkPolicyParams
+	// TLSProfile is the TLS security profile of the cluster, rendered into the TLS configuration of the components
+	TLSProfile TLSProfileParams
 
 	APIServerServiceDNSName string
 	MLflow                  *dspa.MLflowConfig
@@ -117,6 +148,11 @@ type DSPAParams struct {
 	// CompiledPipelineSpecPatch is a JSON patch applied to all compiled pipeline specs
 	// Used for global workflow configuration like TTL strategy
 	CompiledPipelineSpecPatch string
+	// WorkflowControllerInstanceID is the Argo instanceID of the WorkflowController, set when its workflows are
+	// isolated from the other workflow controllers of the cluster
+	WorkflowControllerInstanceID string
+	// WorkflowInstanceIDWebhook is set when the webhook labeling the workflows of the isolated DSPAs is applied
+	WorkflowInstanceIDWebhook WorkflowInstanceIDWebhookParams
 
 	// PlatformVersion is DSPO.PlatformVersion from operator config (default + quote-trimmed). Used for sample_config and managed pipeline upload tags.
 	PlatformVersion string
@@ -127,11 +163,33 @@ type DSPAParams struct {
 	// operator process environment and forwarded to the managed-pipelines init
 	// container when enabled.
 	ManagedPipelineImageEnvVars []ManagedPipelineImageEnvVar
+	// Content hashes of the user provided Secrets and ConfigMaps consumed by each component, stamped on their pod
+	// templates so that changes to credentials, database extra params or CA bundles roll the components reading them.
+	APIServerContentHash          string
+	PersistenceAgentContentHash   string
+	ScheduledWorkflowContentHash  string
+	WorkflowControllerContentHash string
+	// SecretVersions holds the UID and resourceVersion of the Secrets read through RetrieveSecret, by name. The content
+	// hashes are computed from them, so that the secret values are not exposed on the pod templates.
+	SecretVersions map[string]string
+	// Hashes of the last rotation of the generated database and object storage credentials, set when credential
+	// rotation is enabled so that only the deployments consuming the rotated credentials are rolled.
+	DBCredentialsHash            string
+	ObjectStorageCredentialsHash string
+	// ObjectStorageCredentialRotationDeferred is set when the rotation of the Minio keys is due but waits for the
+	// pipeline runs to complete
+	ObjectStorageCredentialRotationDeferred bool
 	// ResolveMLflowEndpoint resolves the MLflow tracking endpoint for AUTODETECT integration.
 	ResolveMLflowEndpoint func(context.Context, string, logr.Logger) (string, error)
+	// MLflowEndpoint is the MLflow tracking endpoint of the API server plugin, allowed by the egress NetworkPolicy
+	MLflowEndpoint string
+	// GetService reads a Service that is not managed by the operator, and so is not cached, e.g. the MLflow Service
+	GetService func(context.Context, types.NamespacedName) (*v1.Service, error)
 }
 
 type DBConnection struct {
+	// Driver is either config.MySQLDriver or config.PostgreSQLDriver
+	Driver            string
 	Host              string
 	Port              string
 	Username          string
@@ -142,6 +200,8 @@ type DBConnection struct {
 	ExtraParams       string
 }
 type ObjectStorageConnection struct {
+	// Provider is the object storage API, one of s3, gcs or azure
+	Provider          string
 	Bucket            string
 	CredentialsSecret *dspa.S3CredentialSecret
 	Host              string
@@ -154,6 +214,39 @@ type ObjectStorageConnection struct {
 	AccessKeyID       string
 	SecretAccessKey   string
 	ExternalRouteURL  string
+	// WebIdentity is only set when the object store is accessed with web identity tokens instead of static keys
+	WebIdentity *dspa.WebIdentity
+	// WebIdentityTokenMountPath is the directory of the projected service account token in the API Server
+	WebIdentityTokenMountPath string
+	GCSCredentialsSecret      *dspa.GCSCredentialSecret
+	// GCSServiceAccountKey is the base64 encoded JSON service account key
+	GCSServiceAccountKey   string
+	AzureCredentialsSecret *dspa.AzureCredentialSecret
+	// AzureAccountName is the storage account of the Azure Blob endpoint
+	AzureAccountName string
+	// AzureAccountKey is the base64 encoded storage account key
+	AzureAccountKey string
+	// ManageBucket is only set when the operator manages the external s3 bucket
+	ManageBucket *dspa.ManageBucket
+	// Encryption is only set when the objects are written with server-side encryption
+	Encryption *dspa.ObjectStorageEncryption
+	// AdditionalStores route the pipeline roots under their prefix to another s3 compatible store
+	AdditionalStores []AdditionalObjectStoreConnection
+}
+
+type AdditionalObjectStoreConnection struct {
+	Name               string
+	Bucket             string
+	Host               string
+	Port               string
+	Scheme             string
+	Region             string
+	Secure             bool
+	Endpoint           string // scheme://host:port
+	PipelineRootPrefix string
+	CredentialsSecret  *dspa.S3CredentialSecret
+	AccessKeyID        string
+	SecretAccessKey    string
 }
 
 type PluginConfig struct {
@@ -188,6 +281,31 @@ func (p *DSPAParams) UsingExternalDB(dsp *dspa.DataSciencePipelinesApplication)
 	return false
 }
 
+// UsingPostgreSQL will return true if the resolved DB connection uses the PostgreSQL driver, otherwise false.
+func (p *DSPAParams) UsingPostgreSQL() bool {
+	return p.DBConnection.Driver == config.PostgreSQLDriver
+}
+
+// UsingGeneratedDBCredentials will return true if the operator generates the MariaDB password, otherwise false.
+func (p *DSPAParams) UsingGeneratedDBCredentials(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.PasswordSecret == nil
+}
+
+// UsingGeneratedObjectStorageCredentials will return true if the operator generates the Minio keys, otherwise false.
+func (p *DSPAParams) UsingGeneratedObjectStorageCredentials(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.S3CredentialSecret == nil
+}
+
+// UsingMariaDBRestore will return true if a restore from backup is configured for an operator deployed MariaDB, otherwise false.
+func (p *DSPAParams) UsingMariaDBRestore(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy && p.MariaDB.RestoreFrom != nil
+}
+
+// UsingMariaDBBackup will return true if scheduled backups are configured for an operator deployed MariaDB, otherwise false.
+func (p *DSPAParams) UsingMariaDBBackup(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy && p.MariaDB.Backup != nil
+}
+
 // DatabaseHealthCheckDisabled will return the value if the Database has disableHealthCheck specified in the CR, otherwise false.
 func (p *DSPAParams) DatabaseHealthCheckDisabled(dsp *dspa.DataSciencePipelinesApplication) bool {
 	if dsp.Spec.Database != nil {
@@ -204,6 +322,13 @@ func (p *DSPAParams) UsingExternalStorage(dsp *dspa.DataSciencePipelinesApplicat
 	return false
 }
 
+// UsingWebIdentityStorageCredentials will return true if the external object storage is accessed with web identity
+// tokens instead of static keys, otherwise false.
+func (p *DSPAParams) UsingWebIdentityStorageCredentials(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return p.UsingExternalStorage(dsp) &&
+		dsp.Spec.ObjectStorage.ExternalStorage.CredentialsMode == config.ObjectStorageCredentialsModeWebIdentity
+}
+
 // ObjectStorageHealthCheckDisabled will return the value if the Object Storage has disableHealthCheck specified in the CR, otherwise false.
 func (p *DSPAParams) ObjectStorageHealthCheckDisabled(dsp *dspa.DataSciencePipelinesApplication) bool {
 	if dsp.Spec.ObjectStorage != nil {
@@ -212,6 +337,14 @@ func (p *DSPAParams) ObjectStorageHealthCheckDisabled(dsp *dspa.DataSciencePipel
 	return false
 }
 
+// ObjectStorageHealthCheckMode will return the health check mode specified in the CR, otherwise ReadOnly.
+func (p *DSPAParams) ObjectStorageHealthCheckMode(dsp *dspa.DataSciencePipelinesApplication) string {
+	if dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.HealthCheck != nil && dsp.Spec.ObjectStorage.HealthCheck.Mode != "" {
+		return dsp.Spec.ObjectStorage.HealthCheck.Mode
+	}
+	return config.ObjectStorageHealthCheckModeReadOnly
+}
+
 // ExternalRouteEnabled will return true if an external route is enabled in the CR, otherwise false.
 func (p *DSPAParams) ExternalRouteEnabled(dsp *dspa.DataSciencePipelinesApplication) bool {
 	if dsp.Spec.ObjectStorage != nil {
@@ -253,6 +386,10 @@ func (p *DSPAParams) RetrieveSecret(ctx context.Context, client client.Client, s
 		log.V(1).Info(fmt.Sprintf("Unable to retrieve secret [%s].", secretName))
 		return "", err
 	}
+	if p.SecretVersions == nil {
+		p.SecretVersions = map[string]string{}
+	}
+	p.SecretVersions[secretName] = string(secret.UID) + "/" + secret.ResourceVersion
 	return base64.StdEncoding.EncodeToString(secret.Data[secretKey]), nil
 }
 
@@ -320,12 +457,19 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 		p.DBConnection.Username = dsp.Spec.Database.ExternalDB.Username
 		p.DBConnection.DBName = dsp.Spec.Database.ExternalDB.DBName
 		p.DBConnection.CredentialsSecret = dsp.Spec.Database.ExternalDB.PasswordSecret
+		p.DBConnection.Driver = dsp.Spec.Database.ExternalDB.Driver
+		setStringDefault(config.MySQLDriver, &p.DBConnection.Driver)
 
 		// Assume default external connection is tls enabled
 		// user can override this via CustomExtraParams field
 		tlsParams := config.DBExtraParams{
 			"tls": "true",
 		}
+		if p.UsingPostgreSQL() {
+			tlsParams = config.DBExtraParams{
+				"sslmode": "verify-full",
+			}
+		}
 		dbExtraParams, err := config.GetDefaultDBExtraParams(tlsParams, log)
 		if err != nil {
 			log.Error(err, "Unexpected error encountered while retrieving DBExtraparams")
@@ -364,6 +508,19 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 		setStringDefault(config.MariaDBUser, &p.MariaDB.Username)
 		setStringDefault(config.MariaDBName, &p.MariaDB.DBName)
 		setResourcesDefault(config.MariaDBResourceRequirements, &p.MariaDB.Resources)
+		if p.MariaDB.Backup != nil {
+			setStringDefault(config.MariaDBBackupDefaultSchedule, &p.MariaDB.Backup.Schedule)
+			setStringDefault(config.MariaDBBackupDefaultObjectStoragePrefix, &p.MariaDB.Backup.ObjectStoragePrefix)
+			setStringDefault(config.GetStringConfigWithDefault(config.MariaDBBackupImagePath, config.DefaultImageValue), &p.MariaDB.Backup.Image)
+			if p.MariaDB.Backup.RetentionCount < 1 {
+				p.MariaDB.Backup.RetentionCount = config.MariaDBBackupDefaultRetentionCount
+			}
+			p.MariaDB.Backup.ObjectStoragePrefix = strings.Trim(p.MariaDB.Backup.ObjectStoragePrefix, "/")
+		}
+		if p.MariaDB.RestoreFrom != nil {
+			setStringDefault(config.GetStringConfigWithDefault(config.MariaDBBackupImagePath, config.DefaultImageValue), &p.MariaDB.RestoreFrom.Image)
+			p.MariaDB.RestoreFrom.ObjectStorageKey = strings.TrimPrefix(p.MariaDB.RestoreFrom.ObjectStorageKey, "/")
+		}
 
 		p.DBConnection.Host = fmt.Sprintf(
 			"%s.%s.svc.cluster.local",
@@ -371,6 +528,7 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 			p.Namespace,
 		)
 		p.DBConnection.Port = config.MariaDBHostPort
+		p.DBConnection.Driver = config.MySQLDriver
 		p.DBConnection.Username = p.MariaDB.Username
 		p.DBConnection.DBName = p.MariaDB.DBName
 		// By Default OOB mariadb is not tls enabled
@@ -429,6 +587,10 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 // If DSPO is managing a dynamically created secret, then SetupObjectParams generates the creds.
 func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
 
+	if err := p.setupAdditionalObjectStores(ctx, dsp, client, log); err != nil {
+		return err
+	}
+
 	usingExternalObjectStorage := p.UsingExternalStorage(dsp)
 	if usingExternalObjectStorage {
 		// Assume validation for CR ensures these values exist
@@ -453,7 +615,45 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 
 		// Port can be empty, which is fine.
 		p.ObjectStorageConnection.Port = dsp.Spec.ObjectStorage.ExternalStorage.Port
+
+		p.ObjectStorageConnection.Provider = dsp.Spec.ObjectStorage.ExternalStorage.Provider
+		setStringDefault(config.ObjectStorageProviderS3, &p.ObjectStorageConnection.Provider)
+		if p.ObjectStorageConnection.Provider != config.ObjectStorageProviderS3 &&
+			p.ObjectStorageHealthCheckMode(dsp) == config.ObjectStorageHealthCheckModeReadWrite {
+			return fmt.Errorf("objectStorage healthCheck mode is %s, which is only supported by the %s provider, but the "+
+				"externalStorage provider is %s", config.ObjectStorageHealthCheckModeReadWrite, config.ObjectStorageProviderS3,
+				p.ObjectStorageConnection.Provider)
+		}
+		switch p.ObjectStorageConnection.Provider {
+		case config.ObjectStorageProviderGCS:
+			return p.setupGCSObjectParams(ctx, dsp, client, log)
+		case config.ObjectStorageProviderAzure:
+			return p.setupAzureObjectParams(ctx, dsp, client, log)
+		}
+		p.ObjectStorageConnection.ManageBucket = dsp.Spec.ObjectStorage.ExternalStorage.ManageBucket.DeepCopy()
+		if err := p.setupObjectStorageEncryption(dsp); err != nil {
+			return err
+		}
+
+		if p.UsingWebIdentityStorageCredentials(dsp) {
+			webIdentity := dsp.Spec.ObjectStorage.ExternalStorage.WebIdentity
+			if webIdentity == nil || webIdentity.RoleARN == "" {
+				return fmt.Errorf("externalStorage credentialsMode is %s, but no webIdentity roleArn was provided in the DSPA CR Spec",
+					config.ObjectStorageCredentialsModeWebIdentity)
+			}
+			p.ObjectStorageConnection.WebIdentity = webIdentity.DeepCopy()
+			setStringDefault(config.DefaultWebIdentityAudience, &p.ObjectStorageConnection.WebIdentity.Audience)
+			p.ObjectStorageConnection.WebIdentityTokenMountPath = config.WebIdentityTokenMountPath
+			// No keys to retrieve, components exchange their service account token for temporary credentials
+			p.setupObjectStorageEndpoint(ctx, dsp, client, log)
+			return nil
+		}
+
 		p.ObjectStorageConnection.CredentialsSecret = dsp.Spec.ObjectStorage.ExternalStorage.S3CredentialSecret
+		if p.ObjectStorageConnection.CredentialsSecret == nil {
+			return fmt.Errorf("externalStorage credentialsMode is %s, but no s3CredentialsSecret was provided in the DSPA CR Spec",
+				config.ObjectStorageCredentialsModeStatic)
+		}
 
 		// Retrieve ObjStore Creds from specified secret.  Ignore error if the secret simply doesn't exist (will be created later)
 		accesskey, err := p.RetrieveSecret(ctx, client, p.ObjectStorageConnection.CredentialsSecret.SecretName, p.ObjectStorageConnection.CredentialsSecret.AccessKey, log)
@@ -486,6 +686,7 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 		setStringDefault(config.MinioDefaultBucket, &p.Minio.Bucket)
 		setResourcesDefault(config.MinioResourceRequirements, &p.Minio.Resources)
 
+		p.ObjectStorageConnection.Provider = config.ObjectStorageProviderS3
 		p.ObjectStorageConnection.Bucket = config.MinioDefaultBucket
 		p.ObjectStorageConnection.Host = fmt.Sprintf(
 			"%s.%s.svc.cluster.local",
@@ -516,6 +717,149 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 
 	}
 
+	p.setupObjectStorageEndpoint(ctx, dsp, client, log)
+
+	if p.ObjectStorageConnection.AccessKeyID == "" || p.ObjectStorageConnection.SecretAccessKey == "" {
+		return fmt.Errorf("object storage password from secret [%s] for keys [%s, %s] was not "+
+			"successfully retrieved, ensure that the secret with this key exist",
+			p.ObjectStorageConnection.CredentialsSecret.SecretName,
+			p.ObjectStorageConnection.CredentialsSecret.AccessKey, p.ObjectStorageConnection.CredentialsSecret.SecretKey)
+	}
+	return nil
+
+}
+
+func (p *DSPAParams) setupGCSObjectParams(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
+	secretRef := dsp.Spec.ObjectStorage.ExternalStorage.GCSCredentialSecret
+	if secretRef == nil {
+		return fmt.Errorf("externalStorage provider is %s, but no gcsCredentialsSecret was provided in the DSPA CR Spec",
+			config.ObjectStorageProviderGCS)
+	}
+	p.ObjectStorageConnection.GCSCredentialsSecret = secretRef
+
+	serviceAccountKey, err := p.RetrieveSecret(ctx, client, secretRef.SecretName, secretRef.ServiceAccountKey, log)
+	if err != nil && !apierrs.IsNotFound(err) {
+		log.Error(err, "Unexpected error encountered while fetching Object Storage Secret")
+		return err
+	}
+	p.ObjectStorageConnection.GCSServiceAccountKey = serviceAccountKey
+	p.setupObjectStorageEndpoint(ctx, dsp, client, log)
+
+	if serviceAccountKey == "" {
+		return fmt.Errorf("object storage service account key from secret [%s] for key [%s] was not "+
+			"successfully retrieved, ensure that the secret with this key exist", secretRef.SecretName, secretRef.ServiceAccountKey)
+	}
+	return nil
+}
+
+func (p *DSPAParams) setupAzureObjectParams(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
+	secretRef := dsp.Spec.ObjectStorage.ExternalStorage.AzureCredentialSecret
+	if secretRef == nil {
+		return fmt.Errorf("externalStorage provider is %s, but no azureCredentialsSecret was provided in the DSPA CR Spec",
+			config.ObjectStorageProviderAzure)
+	}
+	p.ObjectStorageConnection.AzureCredentialsSecret = secretRef
+	// Azure Blob endpoints are of the form <account>.blob.core.windows.net
+	p.ObjectStorageConnection.AzureAccountName = strings.Split(p.ObjectStorageConnection.Host, ".")[0]
+
+	accountKey, err := p.RetrieveSecret(ctx, client, secretRef.SecretName, secretRef.AccountKey, log)
+	if err != nil && !apierrs.IsNotFound(err) {
+		log.Error(err, "Unexpected error encountered while fetching Object Storage Secret")
+		return err
+	}
+	p.ObjectStorageConnection.AzureAccountKey = accountKey
+	p.setupObjectStorageEndpoint(ctx, dsp, client, log)
+
+	if accountKey == "" {
+		return fmt.Errorf("object storage account key from secret [%s] for key [%s] was not "+
+			"successfully retrieved, ensure that the secret with this key exist", secretRef.SecretName, secretRef.AccountKey)
+	}
+	return nil
+}
+
+// setupObjectStorageEncryption sets the server-side encryption of the s3 object storage. SSE-C is refused for the
+// DSPAs stored before it was removed from the CRD: the API server has no customer provided key to read the step logs
+// archived by Argo Workflows.
+func (p *DSPAParams) setupObjectStorageEncryption(dsp *dspa.DataSciencePipelinesApplication) error {
+	encryption := dsp.Spec.ObjectStorage.ExternalStorage.Encryption
+	if encryption == nil {
+		return nil
+	}
+	p.ObjectStorageConnection.Encryption = encryption.DeepCopy()
+
+	switch encryption.Type {
+	case config.ObjectStorageEncryptionSSEKMS:
+		if encryption.KMSKeyID == "" {
+			return fmt.Errorf("externalStorage encryption is %s, but no kmsKeyId was provided in the DSPA CR Spec", encryption.Type)
+		}
+	case config.ObjectStorageEncryptionSSEC:
+		return fmt.Errorf("externalStorage encryption %s is not supported, the API server could not read the step logs "+
+			"archived with a customer provided key", encryption.Type)
+	}
+	return nil
+}
+
+// setupAdditionalObjectStores populates the connections of the additionalStores. Credentials of a store are left empty
+// when its secret does not exist, the object storage health check reports the store as unavailable.
+func (p *DSPAParams) setupAdditionalObjectStores(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
+	stores := dsp.Spec.ObjectStorage.AdditionalStores
+	if len(stores) == 0 {
+		return nil
+	}
+	if p.UsingExternalStorage(dsp) {
+		provider := dsp.Spec.ObjectStorage.ExternalStorage.Provider
+		if provider != "" && provider != config.ObjectStorageProviderS3 {
+			return fmt.Errorf("objectStorage additionalStores require the %s provider for externalStorage, found %s",
+				config.ObjectStorageProviderS3, provider)
+		}
+	}
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.CustomKfpLauncherConfigMap != "" {
+		return fmt.Errorf("objectStorage additionalStores cannot be combined with apiServer customKfpLauncherConfigMap [%s]",
+			dsp.Spec.APIServer.CustomKfpLauncherConfigMap)
+	}
+
+	p.ObjectStorageConnection.AdditionalStores = make([]AdditionalObjectStoreConnection, 0, len(stores))
+	for _, store := range stores {
+		if store.S3CredentialSecret == nil {
+			return fmt.Errorf("objectStorage additionalStore [%s] has no s3CredentialsSecret", store.Name)
+		}
+		connection := AdditionalObjectStoreConnection{
+			Name:               store.Name,
+			Bucket:             store.Bucket,
+			Host:               store.Host,
+			Port:               store.Port,
+			Scheme:             store.Scheme,
+			Region:             store.Region,
+			PipelineRootPrefix: strings.Trim(store.PipelineRootPrefix, "/"),
+			CredentialsSecret:  store.S3CredentialSecret,
+		}
+		setStringDefault("https", &connection.Scheme)
+		setStringDefault("auto", &connection.Region)
+		connection.Secure = connection.Scheme == "https"
+		connection.Endpoint = fmt.Sprintf("%s://%s", connection.Scheme, connection.Host)
+		if connection.Port != "" {
+			connection.Endpoint = fmt.Sprintf("%s:%s", connection.Endpoint, connection.Port)
+		}
+
+		accesskey, err := p.RetrieveSecret(ctx, client, store.S3CredentialSecret.SecretName, store.S3CredentialSecret.AccessKey, log)
+		if err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "Unexpected error encountered while fetching Object Storage Secret")
+			return err
+		}
+		secretkey, err := p.RetrieveSecret(ctx, client, store.S3CredentialSecret.SecretName, store.S3CredentialSecret.SecretKey, log)
+		if err != nil && !apierrs.IsNotFound(err) {
+			log.Error(err, "Unexpected error encountered while fetching Object Storage Secret")
+			return err
+		}
+		connection.AccessKeyID = accesskey
+		connection.SecretAccessKey = secretkey
+		p.ObjectStorageConnection.AdditionalStores = append(p.ObjectStorageConnection.AdditionalStores, connection)
+	}
+	return nil
+}
+
+// setupObjectStorageEndpoint sets the object storage endpoint, which is the Minio route instead when it is enabled.
+func (p *DSPAParams) setupObjectStorageEndpoint(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) {
 	if p.ExternalRouteEnabled(dsp) {
 		route, err := p.RetrieveAndSetExternalRoute(ctx, client, log)
 		if err != nil {
@@ -544,15 +888,6 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 	}
 
 	p.ObjectStorageConnection.Endpoint = endpoint
-
-	if p.ObjectStorageConnection.AccessKeyID == "" || p.ObjectStorageConnection.SecretAccessKey == "" {
-		return fmt.Errorf("object storage password from secret [%s] for keys [%s, %s] was not "+
-			"successfully retrieved, ensure that the secret with this key exist",
-			p.ObjectStorageConnection.CredentialsSecret.SecretName,
-			p.ObjectStorageConnection.CredentialsSecret.AccessKey, p.ObjectStorageConnection.CredentialsSecret.SecretKey)
-	}
-	return nil
-
 }
 
 func (p *DSPAParams) SetupMLMD(dsp *dspa.DataSciencePipelinesApplication, log logr.Logger) error {
@@ -595,6 +930,37 @@ func (p *DSPAParams) SetupMLMD(dsp *dspa.DataSciencePipelinesApplication, log lo
 	return nil
 }
 
+// SetupAPIServerHighAvailability resolves the replicas of the API Server. The high availability preset raises them to
+// at least 2, autoscaling hands them over to a HorizontalPodAutoscaler.
+func (p *DSPAParams) SetupAPIServerHighAvailability() {
+	p.APIServerReplicas = 1
+	p.APIServerHighlyAvailable = false
+	p.APIServerAutoscaling = nil
+	if p.APIServer == nil {
+		return
+	}
+	if p.APIServer.Replicas != nil {
+		p.APIServerReplicas = *p.APIServer.Replicas
+	}
+
+	highAvailability := p.APIServer.HighAvailability
+	if highAvailability != nil && highAvailability.Enabled {
+		if highAvailability.Autoscaling != nil {
+			p.APIServerAutoscaling = highAvailability.Autoscaling.DeepCopy()
+			if p.APIServerAutoscaling.MinReplicas == nil {
+				p.APIServerAutoscaling.MinReplicas = util.Int32Pointer(config.APIServerHighAvailabilityMinReplicas)
+			}
+			if p.APIServerAutoscaling.TargetCPUUtilizationPercentage == nil {
+				p.APIServerAutoscaling.TargetCPUUtilizationPercentage = util.Int32Pointer(config.APIServerAutoscalingDefaultTargetCPUPercent)
+			}
+			p.APIServerReplicas = 0
+		} else if p.APIServerReplicas < config.APIServerHighAvailabilityMinReplicas {
+			p.APIServerReplicas = config.APIServerHighAvailabilityMinReplicas
+		}
+	}
+	p.APIServerHighlyAvailable = p.APIServerReplicas > 1 || p.APIServerAutoscaling != nil
+}
+
 func (p *DSPAParams) SetupOwner(dsp *dspa.DataSciencePipelinesApplication) {
 	p.IncludeOwnerReference = config.GetBoolConfigWithDefault(config.ApiServerIncludeOwnerReferenceConfigName, config.DefaultApiServerIncludeOwnerReferenceConfigName)
 
@@ -626,11 +992,44 @@ func (p *DSPAParams) SetupCompiledPipelineSpecPatch(log logr.Logger) {
 		}
 	}
 
-	// Future extensibility: add more patch fields here as needed
-	// Example:
-	// if p.APIServer != nil && p.APIServer.PodGCStrategy != nil {
-	//     patch["podGC"] = map[string]interface{}{"strategy": *p.APIServer.PodGCStrategy}
-	// }
+	// Label the runs with the instanceID of the WorkflowController of the DSPA. The label must be set when the
+	// workflows are created, since the workflow controllers select the workflows they run by this label. The
+	// workflows the ScheduledWorkflow controller creates for recurring runs are labeled by the workflow instanceID
+	// webhook.
+	if p.WorkflowControllerInstanceID != "" {
+		patch["metadata"] = map[string]interface{}{
+			"labels": map[string]interface{}{
+				argoControllerInstanceIDLabel: p.WorkflowControllerInstanceID,
+			},
+		}
+	}
+
+	if p.APIServer != nil && p.APIServer.RunDefaults != nil {
+		runDefaults := p.APIServer.RunDefaults
+		if runDefaults.PodGCStrategy != "" {
+			patch["podGC"] = map[string]interface{}{"strategy": string(runDefaults.PodGCStrategy)}
+		}
+		if runDefaults.ActiveDeadlineSeconds != nil {
+			patch["activeDeadlineSeconds"] = *runDefaults.ActiveDeadlineSeconds
+		}
+		if runDefaults.RetryStrategy != nil {
+			patch["retryStrategy"] = runRetryStrategyPatch(runDefaults.RetryStrategy)
+		}
+		// Argo Workflows expects the podSpecPatch as a serialized pod spec
+		if podSpecPatch := runDefaults.PodSpecPatch; podSpecPatch != nil && len(podSpecPatch.Raw) > 0 {
+			compacted := &bytes.Buffer{}
+			if err := json.Compact(compacted, podSpecPatch.Raw); err != nil {
+				log.Error(err, "Error serializing the podSpecPatch of the run defaults")
+			} else {
+				patch["podSpecPatch"] = compacted.String()
+			}
+		}
+		if runDefaults.PodSpec != nil {
+			for field, value := range runPodSpecPatch(runDefaults.PodSpec) {
+				patch[field] = value
+			}
+		}
+	}
 
 	if len(patch) == 0 {
 		p.CompiledPipelineSpecPatch = ""
@@ -646,6 +1045,87 @@ func (p *DSPAParams) SetupCompiledPipelineSpecPatch(log logr.Logger) {
 	p.CompiledPipelineSpecPatch = string(patchJSON)
 }
 
+// runRetryStrategyPatch converts the retry strategy of the run defaults to the retryStrategy of an Argo Workflow
+func runRetryStrategyPatch(retryStrategy *dspa.RunRetryStrategy) map[string]interface{} {
+	patch := map[string]interface{}{"limit": retryStrategy.Limit}
+	if retryStrategy.RetryPolicy != "" {
+		patch["retryPolicy"] = string(retryStrategy.RetryPolicy)
+	}
+	if backoff := retryStrategy.Backoff; backoff != nil {
+		backoffPatch := map[string]interface{}{"duration": backoff.Duration.Duration.String()}
+		if backoff.Factor != nil {
+			backoffPatch["factor"] = *backoff.Factor
+		}
+		if backoff.MaxDuration != nil {
+			backoffPatch["maxDuration"] = backoff.MaxDuration.Duration.String()
+		}
+		patch["backoff"] = backoffPatch
+	}
+	return patch
+}
+
+// runPodSpecPatch converts the pod settings of the run defaults to the fields of an Argo Workflow spec. Argo Workflows
+// uses them for the steps that do not set their own.
+func runPodSpecPatch(podSpec *dspa.RunPodSpec) map[string]interface{} {
+	patch := map[string]interface{}{}
+	if len(podSpec.NodeSelector) > 0 {
+		patch["nodeSelector"] = podSpec.NodeSelector
+	}
+	if len(podSpec.Tolerations) > 0 {
+		patch["tolerations"] = podSpec.Tolerations
+	}
+	if podSpec.PriorityClassName != "" {
+		patch["podPriorityClassName"] = podSpec.PriorityClassName
+	}
+	if podSpec.ServiceAccountName != "" {
+		patch["serviceAccountName"] = podSpec.ServiceAccountName
+	}
+	if podSpec.SecurityContext != nil {
+		patch["securityContext"] = podSpec.SecurityContext
+	}
+	if len(podSpec.ImagePullSecrets) > 0 {
+		patch["imagePullSecrets"] = podSpec.ImagePullSecrets
+	}
+	return patch
+}
+
+// validateRunDefaults checks the run defaults that the CRD schema cannot, so that an invalid patch is reported on
+// the DSPA instead of failing every run submitted to the API Server.
+func validateRunDefaults(runDefaults *dspa.RunDefaults) error {
+	if runDefaults == nil {
+		return nil
+	}
+	if runDefaults.RetryStrategy != nil && runDefaults.RetryStrategy.Backoff != nil {
+		backoff := runDefaults.RetryStrategy.Backoff
+		if backoff.Duration.Duration <= 0 {
+			return errors.New("invalid spec.apiServer.runDefaults: retryStrategy.backoff.duration must be positive")
+		}
+		if backoff.MaxDuration != nil && backoff.MaxDuration.Duration < backoff.Duration.Duration {
+			return errors.New("invalid spec.apiServer.runDefaults: retryStrategy.backoff.maxDuration must not be " +
+				"shorter than retryStrategy.backoff.duration")
+		}
+	}
+	if podSpec := runDefaults.PodSpec; podSpec != nil {
+		names := map[string]string{"priorityClassName": podSpec.PriorityClassName, "serviceAccountName": podSpec.ServiceAccountName}
+		for _, field := range []string{"priorityClassName", "serviceAccountName"} {
+			if names[field] == "" {
+				continue
+			}
+			if errs := validation.IsDNS1123Subdomain(names[field]); len(errs) > 0 {
+				return fmt.Errorf("invalid spec.apiServer.runDefaults: podSpec.%s %s: %s", field, names[field], strings.Join(errs, ", "))
+			}
+		}
+	}
+	if podSpecPatch := runDefaults.PodSpecPatch; podSpecPatch != nil && len(podSpecPatch.Raw) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(podSpecPatch.Raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&v1.PodSpec{}); err != nil {
+			return fmt.Errorf("invalid spec.apiServer.runDefaults: podSpecPatch must be a pod spec: %w", err)
+		}
+	}
+	return nil
+}
+
 func setResourcesDefault(defaultValue dspa.ResourceRequirements, value **dspa.ResourceRequirements) {
 	if *value == nil {
 		*value = defaultValue.DeepCopy()
@@ -757,6 +1237,9 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 	} else {
 		p.PodToPodTLS = *dsp.Spec.PodToPodTLS
 	}
+	if err := p.SetupCertificateProvider(dsp); err != nil {
+		return err
+	}
 
 	integrationMode := dspa.AutoDetect
 	injectUserEnvVars := false
@@ -777,6 +1260,14 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 
 	log := loggr.WithValues("namespace", p.Namespace).WithValues("dspa_name", p.Name)
 
+	p.SetupWorkflowControllerInstanceID(dsp, log)
+
+	if p.APIServer != nil {
+		if err := validateRunDefaults(p.APIServer.RunDefaults); err != nil {
+			return err
+		}
+	}
+
 	// Build compiled pipeline spec patch from DSPA fields
 	p.SetupCompiledPipelineSpecPatch(log)
 
@@ -874,6 +1365,7 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 								log.Info("Failed to build MLflow plugin config. MLflow API server plugin will not be enabled.", "error", err)
 							} else {
 								p.APIServerPluginsJson = pluginCfg
+								p.MLflowEndpoint = mlflowEndpoint
 							}
 						}
 					}
@@ -965,10 +1457,24 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 			}
 		}
 
-		// If PodToPodTLS is enabled, we need to include service-ca ca-bundles to recognize the certs
-		// that are signed by service-ca. These can be accessed via "openshift-service-ca.crt"
-		// configmap.
-		if p.PodToPodTLS {
+		// If PodToPodTLS is enabled with cert-manager, we need to include the CA of the issuer to recognize
+		// the certs it signs. It is only known once the first certificate is issued, the DSPA is reconciled
+		// again then.
+		if p.PodToPodTLS && p.CertManager != nil {
+			certManagerCA, certManagerCAErr := p.LoadCertManagerCA(ctx, client)
+			if certManagerCAErr != nil {
+				log.Info(fmt.Sprintf("Encountered error when attempting to fetch the CA of the certificates issued by cert-manager. Error: %v", certManagerCAErr))
+				return certManagerCAErr
+			}
+			if strings.TrimSpace(certManagerCA) != "" {
+				p.APICustomPemCerts = append(p.APICustomPemCerts, []byte(certManagerCA))
+			} else {
+				log.Info("The certificates of the DSPA were not issued by cert-manager yet, their CA will be trusted once they are.")
+			}
+		} else if p.PodToPodTLS {
+			// If PodToPodTLS is enabled, we need to include service-ca ca-bundles to recognize the certs
+			// that are signed by service-ca. These can be accessed via "openshift-service-ca.crt"
+			// configmap.
 			serviceCA, serviceCACfgErr := util.GetConfigMap(ctx, config.OpenshiftServiceCAConfigMapName, p.Namespace, client)
 			if serviceCACfgErr != nil {
 				log.Info(fmt.Sprintf("Encountered error when attempting to fetch ConfigMap: [%s]. Error: %v", config.OpenshiftServiceCAConfigMapName, serviceCA))
@@ -1126,11 +1632,80 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 		return err
 	}
 
+	err = p.SetupNetworkPolicy(ctx, dsp, client)
+	if err != nil {
+		return err
+	}
+
+	p.SetupCommonMetadata(dsp)
+	p.SetupAPIServerHighAvailability()
+
+	err = p.SetupPodPlacement(dsp)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupPodTemplateOverrides(dsp)
+	if err != nil {
+		return err
+	}
+
 	p.SetupOwner(dsp)
+	p.SetContentHashes(dsp)
 
 	return nil
 }
 
+// SetContentHashes computes the content hashes of the user provided Secrets and ConfigMaps consumed by each component.
+// Secrets are hashed by their UID and resourceVersion rather than by their values. Credentials generated by the
+// operator are not included, they only change through credential rotation, which rolls the components through
+// DBCredentialsHash and ObjectStorageCredentialsHash instead.
+func (p *DSPAParams) SetContentHashes(dsp *dspa.DataSciencePipelinesApplication) {
+	var dbCredentials, dbExtraParams, objectStorageCredentials string
+	if !p.UsingGeneratedDBCredentials(dsp) && p.DBConnection.CredentialsSecret != nil {
+		dbCredentials = p.SecretVersions[p.DBConnection.CredentialsSecret.Name]
+	}
+	if dsp.Spec.Database != nil && dsp.Spec.Database.CustomExtraParams != nil {
+		dbExtraParams = p.DBConnection.ExtraParams
+	}
+	switch {
+	case p.ObjectStorageConnection.Provider == config.ObjectStorageProviderGCS:
+		if p.ObjectStorageConnection.GCSCredentialsSecret != nil {
+			objectStorageCredentials = p.SecretVersions[p.ObjectStorageConnection.GCSCredentialsSecret.SecretName]
+		}
+	case p.ObjectStorageConnection.Provider == config.ObjectStorageProviderAzure:
+		if p.ObjectStorageConnection.AzureCredentialsSecret != nil {
+			objectStorageCredentials = p.SecretVersions[p.ObjectStorageConnection.AzureCredentialsSecret.SecretName]
+		}
+	case !p.UsingGeneratedObjectStorageCredentials(dsp) && p.ObjectStorageConnection.CredentialsSecret != nil:
+		objectStorageCredentials = p.SecretVersions[p.ObjectStorageConnection.CredentialsSecret.SecretName]
+	}
+
+	// CA bundles collected from ConfigMaps are not in a stable order
+	pemCerts := make([]string, 0, len(p.APICustomPemCerts))
+	for _, pemCert := range p.APICustomPemCerts {
+		pemCerts = append(pemCerts, string(pemCert))
+	}
+	sort.Strings(pemCerts)
+	caBundle := strings.Join(pemCerts, "\n")
+
+	p.APIServerContentHash = contentHash(dbCredentials, dbExtraParams, objectStorageCredentials, caBundle)
+	p.WorkflowControllerContentHash = contentHash(objectStorageCredentials)
+	// The persistence agent and scheduled workflow only mount the CA bundle for pod to pod TLS
+	if p.PodToPodTLS {
+		p.PersistenceAgentContentHash = contentHash(caBundle)
+		p.ScheduledWorkflowContentHash = contentHash(caBundle)
+	}
+}
+
+// contentHash returns the hash of contents, or an empty string if there is no content to hash.
+func contentHash(contents ...string) string {
+	if strings.Join(contents, "") == "" {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(contents, "\x00"))))
+}
+
 func validateMLflowEndpointURL(raw string) error {
 	u, err := url.Parse(strings.TrimSpace(raw))
 	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
@@ -327,7 +327,202 @@ func TestExtractParams_WithWorkspace(t *testing.T) {
 	require.Equal(t, *workspace.VolumeClaimTemplateSpec.StorageClassName, *unmarshalled["VolumeClaimTemplateSpec"].StorageClassName)
 }
 
+func TestSetupDBParams_WithPostgreSQLExternalDB(t *testing.T) {
+	ctx, params, client := CreateNewTestObjects()
+
+	dspa := testutil.CreateEmptyDSPA()
+	dspa.Spec.Database = &dspav1.Database{
+		ExternalDB: &dspav1.ExternalDB{
+			Driver:   "postgres",
+			Host:     "postgres.example.com",
+			Port:     "5432",
+			Username: "pguser",
+			DBName:   "pipelines",
+			PasswordSecret: &dspav1.SecretKeyValue{
+				Name: "pg-secret",
+				Key:  "password",
+			},
+		},
+	}
+	params.Name = dspa.Name
+	params.Namespace = dspa.Namespace
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-secret", Namespace: dspa.Namespace},
+		Data:       map[string][]byte{"password": []byte("password")},
+	}
+	err := client.Create(ctx, secret)
+	require.NoError(t, err)
+
+	err = params.SetupDBParams(ctx, dspa, client.Client, client.Log)
+	require.NoError(t, err)
+	assert.True(t, params.UsingPostgreSQL())
+	assert.Equal(t, "postgres", params.DBConnection.Driver)
+	assert.Equal(t, `{"sslmode":"verify-full"}`, params.DBConnection.ExtraParams)
+}
+
+func TestSetupDBParams_ExternalDBDefaultsToMySQL(t *testing.T) {
+	ctx, params, client := CreateNewTestObjects()
+
+	dspa := testutil.CreateEmptyDSPA()
+	dspa.Spec.Database = &dspav1.Database{
+		ExternalDB: &dspav1.ExternalDB{
+			Host:     "mysql.example.com",
+			Port:     "3306",
+			Username: "user",
+			DBName:   "pipelines",
+			PasswordSecret: &dspav1.SecretKeyValue{
+				Name: "mysql-secret",
+				Key:  "password",
+			},
+		},
+	}
+	params.Name = dspa.Name
+	params.Namespace = dspa.Namespace
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-secret", Namespace: dspa.Namespace},
+		Data:       map[string][]byte{"password": []byte("password")},
+	}
+	err := client.Create(ctx, secret)
+	require.NoError(t, err)
+
+	err = params.SetupDBParams(ctx, dspa, client.Client, client.Log)
+	require.NoError(t, err)
+	assert.False(t, params.UsingPostgreSQL())
+	assert.Equal(t, "mysql", params.DBConnection.Driver)
+	assert.Equal(t, `{"tls":"true"}`, params.DBConnection.ExtraParams)
+}
+
+func TestSetContentHashes_ExternalDBCredentials(t *testing.T) {
+	dspa := testutil.CreateEmptyDSPA()
+	dspa.Spec.Database = &dspav1.Database{
+		ExternalDB: &dspav1.ExternalDB{
+			PasswordSecret: &dspav1.SecretKeyValue{Name: "mysql-secret", Key: "password"},
+		},
+	}
+	params := &DSPAParams{SecretVersions: map[string]string{"mysql-secret": "uid/1"}}
+	params.DBConnection.CredentialsSecret = dspa.Spec.Database.ExternalDB.PasswordSecret
+	params.DBConnection.Password = "cGFzc3dvcmQ="
+	params.DBConnection.ExtraParams = `{"tls":"true"}`
+
+	params.SetContentHashes(dspa)
+	assert.NotEmpty(t, params.APIServerContentHash)
+	assert.Empty(t, params.PersistenceAgentContentHash)
+	assert.Empty(t, params.ScheduledWorkflowContentHash)
+	initialHash := params.APIServerContentHash
+
+	// Default extra params are not user provided content
+	params.DBConnection.ExtraParams = `{"tls":"false"}`
+	params.SetContentHashes(dspa)
+	assert.Equal(t, initialHash, params.APIServerContentHash)
+
+	// The secret values are not hashed, only the version of the Secret holding them
+	params.DBConnection.Password = "bmV3cGFzc3dvcmQ="
+	params.SetContentHashes(dspa)
+	assert.Equal(t, initialHash, params.APIServerContentHash)
+
+	// A changed password Secret or custom extra params rolls the API server
+	params.SecretVersions["mysql-secret"] = "uid/2"
+	params.SetContentHashes(dspa)
+	assert.NotEqual(t, initialHash, params.APIServerContentHash)
+
+	passwordChangedHash := params.APIServerContentHash
+	customExtraParams := `{"tls":"skip-verify"}`
+	dspa.Spec.Database.CustomExtraParams = &customExtraParams
+	params.DBConnection.ExtraParams = customExtraParams
+	params.SetContentHashes(dspa)
+	assert.NotEqual(t, passwordChangedHash, params.APIServerContentHash)
+}
+
+func TestRetrieveSecretRecordsTheSecretVersion(t *testing.T) {
+	ctx, params, client := CreateNewTestObjects()
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-secret", Namespace: "testnamespace", UID: "mysql-secret-uid"},
+		Data:       map[string][]byte{"password": []byte("password")},
+	}
+	require.NoError(t, client.Create(ctx, secret))
+	params.Namespace = "testnamespace"
+
+	_, err := params.RetrieveSecret(ctx, client.Client, "mysql-secret", "password", client.Log)
+	require.NoError(t, err)
+	initialVersion := params.SecretVersions["mysql-secret"]
+	assert.Equal(t, "mysql-secret-uid/"+secret.ResourceVersion, initialVersion)
+
+	secret.Data["password"] = []byte("newpassword")
+	require.NoError(t, client.Update(ctx, secret))
+	_, err = params.RetrieveSecret(ctx, client.Client, "mysql-secret", "password", client.Log)
+	require.NoError(t, err)
+	assert.NotEqual(t, initialVersion, params.SecretVersions["mysql-secret"])
+}
+
+func TestSetContentHashes_GeneratedCredentialsAreIgnored(t *testing.T) {
+	dspa := testutil.CreateEmptyDSPA()
+	dspa.Spec.Database = &dspav1.Database{MariaDB: &dspav1.MariaDB{Deploy: true}}
+	dspa.Spec.ObjectStorage = &dspav1.ObjectStorage{Minio: &dspav1.Minio{Deploy: true}}
+	params := &DSPAParams{
+		MariaDB: dspa.Spec.Database.MariaDB.DeepCopy(),
+		Minio:   dspa.Spec.ObjectStorage.Minio.DeepCopy(),
+	}
+	params.DBConnection.Password = "Z2VuZXJhdGVk"
+	params.ObjectStorageConnection.AccessKeyID = "Z2VuZXJhdGVk"
+	params.ObjectStorageConnection.SecretAccessKey = "Z2VuZXJhdGVk"
+
+	params.SetContentHashes(dspa)
+	assert.Empty(t, params.APIServerContentHash)
+	assert.Empty(t, params.WorkflowControllerContentHash)
+
+	// CA bundles are hashed independently of the order they were collected in
+	params.PodToPodTLS = true
+	params.APICustomPemCerts = [][]byte{[]byte("cert-a"), []byte("cert-b")}
+	params.SetContentHashes(dspa)
+	assert.NotEmpty(t, params.APIServerContentHash)
+	assert.NotEmpty(t, params.PersistenceAgentContentHash)
+	assert.Equal(t, params.PersistenceAgentContentHash, params.ScheduledWorkflowContentHash)
+	caHash := params.APIServerContentHash
+
+	params.APICustomPemCerts = [][]byte{[]byte("cert-b"), []byte("cert-a")}
+	params.SetContentHashes(dspa)
+	assert.Equal(t, caHash, params.APIServerContentHash)
+}
+
+func TestCredentialsAndCABundleChangesReconcileTheDSPA(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	dspa := testutil.CreateEmptyDSPA()
+	dspa.Spec.DSPVersion = "v2"
+	dspa.Spec.APIServer = &dspav1.APIServer{CABundle: &dspav1.CABundle{ConfigMapName: "custom-ca", ConfigMapKey: "ca.crt"}}
+	dspa.Spec.Database = &dspav1.Database{
+		ExternalDB: &dspav1.ExternalDB{PasswordSecret: &dspav1.SecretKeyValue{Name: "mysql-secret", Key: "password"}},
+	}
+	dspa.Spec.ObjectStorage = &dspav1.ObjectStorage{
+		ExternalStorage: &dspav1.ExternalStorage{
+			S3CredentialSecret: &dspav1.S3CredentialSecret{SecretName: "s3-secret", AccessKey: "k", SecretKey: "s"},
+		},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, dspa))
+
+	for _, secretName := range []string{"mysql-secret", "s3-secret"} {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: dspa.Namespace}}
+		requests := reconciler.dspasReferencingSecret(ctx, secret)
+		require.Len(t, requests, 1, secretName)
+		assert.Equal(t, dspa.Name, requests[0].Name)
+	}
+	unrelated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: dspa.Namespace}}
+	assert.Empty(t, reconciler.dspasReferencingSecret(ctx, unrelated))
+
+	assert.True(t, usesCABundleConfigMap(dspa, "custom-ca"))
+	assert.False(t, usesCABundleConfigMap(dspa, "unrelated"))
+	// The OpenShift service CA is only trusted with pod to pod TLS, which is enabled by default
+	dspa.Spec.PodToPodTLS = nil
+	assert.True(t, usesCABundleConfigMap(dspa, "openshift-service-ca.crt"))
+	podToPodTLS := false
+	dspa.Spec.PodToPodTLS = &podToPodTLS
+	assert.False(t, usesCABundleConfigMap(dspa, "openshift-service-ca.crt"))
+}
+
 func TestSetupCompiledPipelineSpecPatch(t *testing.T) {
+	activeDeadlineSeconds := int64(86400)
+	backoffFactor := int32(2)
 	tt := []struct {
 		name           string
 		params         DSPAParams
@@ -418,6 +613,94 @@ func TestSetupCompiledPipelineSpecPatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "RunDefaults with all fields set",
+			params: DSPAParams{
+				APIServer: &dspav1.APIServer{
+					Deploy:      true,
+					ResourceTTL: &metav1.Duration{Duration: 1 * time.Hour},
+					RunDefaults: &dspav1.RunDefaults{
+						PodGCStrategy:         dspav1.PodGCOnPodSuccess,
+						ActiveDeadlineSeconds: &activeDeadlineSeconds,
+						RetryStrategy: &dspav1.RunRetryStrategy{
+							Limit:       3,
+							RetryPolicy: dspav1.RetryPolicyOnTransientError,
+							Backoff: &dspav1.RunRetryBackoff{
+								Duration:    metav1.Duration{Duration: 30 * time.Second},
+								Factor:      &backoffFactor,
+								MaxDuration: &metav1.Duration{Duration: 10 * time.Minute},
+							},
+						},
+						PodSpecPatch: &runtime.RawExtension{Raw: []byte(`{"priorityClassName": "pipelines"}`)},
+					},
+				},
+			},
+			expectedFields: map[string]interface{}{
+				"ttlStrategy": map[string]interface{}{
+					"secondsAfterCompletion": float64(3600),
+				},
+				"podGC":                 map[string]interface{}{"strategy": "OnPodSuccess"},
+				"activeDeadlineSeconds": float64(86400),
+				"retryStrategy": map[string]interface{}{
+					"limit":       float64(3),
+					"retryPolicy": "OnTransientError",
+					"backoff": map[string]interface{}{
+						"duration":    "30s",
+						"factor":      float64(2),
+						"maxDuration": "10m0s",
+					},
+				},
+				"podSpecPatch": `{"priorityClassName":"pipelines"}`,
+			},
+		},
+		{
+			name: "RunDefaults with pod settings",
+			params: DSPAParams{
+				APIServer: &dspav1.APIServer{
+					Deploy: true,
+					RunDefaults: &dspav1.RunDefaults{PodSpec: &dspav1.RunPodSpec{
+						NodeSelector: map[string]string{"node-pool": "ml"},
+						Tolerations: []v1.Toleration{
+							{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+						},
+						PriorityClassName:  "batch",
+						ServiceAccountName: "pipeline-runner-custom",
+						SecurityContext:    &v1.PodSecurityContext{RunAsNonRoot: testutil.BoolPtr(true)},
+						ImagePullSecrets:   []v1.LocalObjectReference{{Name: "registry-credentials"}},
+					}},
+				},
+			},
+			expectedFields: map[string]interface{}{
+				"nodeSelector": map[string]interface{}{"node-pool": "ml"},
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "nvidia.com/gpu", "operator": "Exists", "effect": "NoSchedule"},
+				},
+				"podPriorityClassName": "batch",
+				"serviceAccountName":   "pipeline-runner-custom",
+				"securityContext":      map[string]interface{}{"runAsNonRoot": true},
+				"imagePullSecrets":     []interface{}{map[string]interface{}{"name": "registry-credentials"}},
+			},
+		},
+		{
+			name: "RunDefaults with empty pod settings - empty patch",
+			params: DSPAParams{
+				APIServer: &dspav1.APIServer{
+					Deploy:      true,
+					RunDefaults: &dspav1.RunDefaults{PodSpec: &dspav1.RunPodSpec{}},
+				},
+			},
+			expectedPatch: "",
+		},
+		{
+			name: "RunDefaults with only a retry limit",
+			params: DSPAParams{
+				APIServer: &dspav1.APIServer{
+					Deploy:      true,
+					RunDefaults: &dspav1.RunDefaults{RetryStrategy: &dspav1.RunRetryStrategy{Limit: 2}},
+				},
+			},
+			expectedPatch: `{"retryStrategy":{"limit":2}}`,
+		},
 	}
 
 	for _, tc := range tt {
@@ -472,6 +755,59 @@ func TestExtractParams_WithoutResourceTTL(t *testing.T) {
 	assert.Empty(t, params.CompiledPipelineSpecPatch)
 }
 
+func TestExtractParams_InvalidRunDefaults(t *testing.T) {
+	tests := map[string]struct {
+		runDefaults   *dspav1.RunDefaults
+		expectedError string
+	}{
+		"unknown podSpecPatch field": {
+			runDefaults:   &dspav1.RunDefaults{PodSpecPatch: &runtime.RawExtension{Raw: []byte(`{"priorityClass": "pipelines"}`)}},
+			expectedError: "invalid spec.apiServer.runDefaults: podSpecPatch must be a pod spec",
+		},
+		"podSpecPatch with a wrong type": {
+			runDefaults:   &dspav1.RunDefaults{PodSpecPatch: &runtime.RawExtension{Raw: []byte(`{"tolerations": "all"}`)}},
+			expectedError: "invalid spec.apiServer.runDefaults: podSpecPatch must be a pod spec",
+		},
+		"invalid priorityClassName": {
+			runDefaults:   &dspav1.RunDefaults{PodSpec: &dspav1.RunPodSpec{PriorityClassName: "Batch_Jobs"}},
+			expectedError: "invalid spec.apiServer.runDefaults: podSpec.priorityClassName Batch_Jobs",
+		},
+		"invalid serviceAccountName": {
+			runDefaults:   &dspav1.RunDefaults{PodSpec: &dspav1.RunPodSpec{ServiceAccountName: "runner/sa"}},
+			expectedError: "invalid spec.apiServer.runDefaults: podSpec.serviceAccountName runner/sa",
+		},
+		"zero backoff duration": {
+			runDefaults: &dspav1.RunDefaults{RetryStrategy: &dspav1.RunRetryStrategy{
+				Limit:   1,
+				Backoff: &dspav1.RunRetryBackoff{},
+			}},
+			expectedError: "invalid spec.apiServer.runDefaults: retryStrategy.backoff.duration must be positive",
+		},
+		"backoff maxDuration shorter than duration": {
+			runDefaults: &dspav1.RunDefaults{RetryStrategy: &dspav1.RunRetryStrategy{
+				Limit: 1,
+				Backoff: &dspav1.RunRetryBackoff{
+					Duration:    metav1.Duration{Duration: time.Minute},
+					MaxDuration: &metav1.Duration{Duration: time.Second},
+				},
+			}},
+			expectedError: "invalid spec.apiServer.runDefaults: retryStrategy.backoff.maxDuration must not be shorter than retryStrategy.backoff.duration",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, params, client := CreateNewTestObjects()
+			dspa := testutil.CreateEmptyDSPA()
+			dspa.Spec.APIServer = &dspav1.APIServer{Deploy: true, RunDefaults: test.runDefaults}
+			dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+
+			err := params.ExtractParams(ctx, dspa, client.Client, client.Log)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedError)
+		})
+	}
+}
+
 func TestExtractParams_MLflowEndpointLookupUsesDSPONamespace(t *testing.T) {
 	t.Setenv("DSPO_NAMESPACE", "opendatahub")
 
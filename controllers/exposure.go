@@ -0,0 +1,306 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	routeCertificateReaderTemplatesDir = "common/route-certificates"
+	routeCertificateReaderNamePrefix   = "ds-pipeline-route-certificates-"
+)
+
+// ExposureParams is the exposure of a component outside of the cluster, resolved against the cluster capabilities
+type ExposureParams struct {
+	// Type is one of Route, Ingress, HTTPRoute or None
+	Type      dspav1.ExposureType
+	Route     RouteParams
+	Ingress   *dspav1.IngressExposure
+	HTTPRoute *dspav1.HTTPRouteExposure
+}
+
+// RouteParams are the options of the Route exposing a component
+type RouteParams struct {
+	Host                  string
+	Termination           dspav1.RouteTLSTermination
+	CertificateSecretName string
+	// PEM encoded CA certificate of the certificate Secret, the certificate and key are read by the router from the
+	// Secret
+	CACertificate string
+}
+
+// exposureTemplates are the templates of the resources exposing a component, which are all named after the component
+type exposureTemplates struct {
+	name      string
+	route     string
+	ingress   string
+	httpRoute string
+	// routeTLSTerminations are the TLS terminations supported by the component, the first one is the default
+	routeTLSTerminations []dspav1.RouteTLSTermination
+}
+
+// resolveExposure returns the exposure of a component. Without an exposure, the legacy route flag of the component
+// decides whether it is exposed with a Route, provided the cluster serves them.
+func (r *DSPAReconciler) resolveExposure(exposure *dspav1.Exposure, legacyRouteEnabled bool) (ExposureParams, error) {
+	if exposure == nil {
+		if legacyRouteEnabled && r.ClusterCapabilities.Routes {
+			return ExposureParams{Type: dspav1.ExposureRoute}, nil
+		}
+		return ExposureParams{Type: dspav1.ExposureNone}, nil
+	}
+
+	resolved := ExposureParams{Type: exposure.Type, Ingress: exposure.Ingress, HTTPRoute: exposure.HTTPRoute}
+	if exposure.Route != nil {
+		resolved.Route.Host = exposure.Route.Host
+		if exposure.Route.TLS != nil {
+			resolved.Route.Termination = exposure.Route.TLS.Termination
+			resolved.Route.CertificateSecretName = exposure.Route.TLS.CertificateSecretName
+		}
+	}
+	switch exposure.Type {
+	case "", dspav1.ExposureAuto:
+		switch {
+		case exposure.HTTPRoute != nil && r.ClusterCapabilities.HTTPRoutes:
+			resolved.Type = dspav1.ExposureHTTPRoute
+		case exposure.Ingress != nil && r.ClusterCapabilities.Ingresses:
+			resolved.Type = dspav1.ExposureIngress
+		case r.ClusterCapabilities.Routes:
+			resolved.Type = dspav1.ExposureRoute
+		default:
+			resolved.Type = dspav1.ExposureNone
+		}
+	case dspav1.ExposureRoute:
+		if !r.ClusterCapabilities.Routes {
+			return ExposureParams{}, fmt.Errorf("exposure type %s requires the OpenShift Route API, which the cluster does not serve", exposure.Type)
+		}
+	case dspav1.ExposureIngress:
+		if exposure.Ingress == nil {
+			return ExposureParams{}, fmt.Errorf("exposure type %s requires ingress to be specified", exposure.Type)
+		}
+		if !r.ClusterCapabilities.Ingresses {
+			return ExposureParams{}, fmt.Errorf("exposure type %s requires the networking.k8s.io/v1 Ingress API, which the cluster does not serve", exposure.Type)
+		}
+	case dspav1.ExposureHTTPRoute:
+		if exposure.HTTPRoute == nil {
+			return ExposureParams{}, fmt.Errorf("exposure type %s requires httpRoute to be specified", exposure.Type)
+		}
+		if !r.ClusterCapabilities.HTTPRoutes {
+			return ExposureParams{}, fmt.Errorf("exposure type %s requires the Gateway API, which is not installed on the cluster", exposure.Type)
+		}
+	}
+	return resolved, nil
+}
+
+// reconcileExposure applies the resource exposing a component and removes the resources of the other exposure types
+func (r *DSPAReconciler) reconcileExposure(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams, exposure *ExposureParams, templates exposureTemplates) error {
+
+	if exposure.Type == dspav1.ExposureRoute {
+		if err := r.setupRouteTLS(ctx, dsp.Namespace, &exposure.Route, templates.routeTLSTerminations); err != nil {
+			return err
+		}
+	}
+
+	resources := []struct {
+		exposureType dspav1.ExposureType
+		served       bool
+		template     string
+		obj          client.Object
+	}{
+		{dspav1.ExposureRoute, r.ClusterCapabilities.Routes, templates.route, &routev1.Route{}},
+		{dspav1.ExposureIngress, r.ClusterCapabilities.Ingresses, templates.ingress, &networkingv1.Ingress{}},
+		{dspav1.ExposureHTTPRoute, r.ClusterCapabilities.HTTPRoutes, templates.httpRoute, newHTTPRoute()},
+	}
+	for _, resource := range resources {
+		if !resource.served {
+			continue
+		}
+		if resource.exposureType == exposure.Type {
+			if err := r.Apply(dsp, params, resource.template); err != nil {
+				return err
+			}
+			continue
+		}
+		namespacedName := types.NamespacedName{Name: templates.name, Namespace: dsp.Namespace}
+		if err := r.DeleteResourceIfItExists(ctx, resource.obj, namespacedName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupRouteTLS defaults the TLS termination of a Route and validates its certificate Secret
+func (r *DSPAReconciler) setupRouteTLS(ctx context.Context, namespace string, route *RouteParams,
+	supportedTerminations []dspav1.RouteTLSTermination) error {
+
+	if route.Termination == "" {
+		route.Termination = supportedTerminations[0]
+	}
+	supported := false
+	for _, termination := range supportedTerminations {
+		if route.Termination == termination {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("route TLS termination %s is not supported by this component, supported terminations are %v",
+			route.Termination, supportedTerminations)
+	}
+
+	route.CACertificate = ""
+	if route.CertificateSecretName == "" {
+		return nil
+	}
+	if route.Termination == dspav1.RouteTLSTerminationPassthrough {
+		return fmt.Errorf("route certificate secret %s cannot be used with the Passthrough TLS termination", route.CertificateSecretName)
+	}
+	secret, err := util.GetSecret(ctx, route.CertificateSecretName, namespace, r.Client)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve route certificate secret %s: %w", route.CertificateSecretName, err)
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return fmt.Errorf("route certificate secret %s must contain the %s and %s keys", route.CertificateSecretName,
+			corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	route.CACertificate = string(secret.Data["ca.crt"])
+	return nil
+}
+
+// routeCertificateSecretNames returns the certificate Secrets of the Routes of a DSPA
+func routeCertificateSecretNames(dspa *dspav1.DataSciencePipelinesApplication) []string {
+	exposures := []*dspav1.Exposure{}
+	if dspa.Spec.APIServer != nil {
+		exposures = append(exposures, dspa.Spec.APIServer.Exposure)
+	}
+	if dspa.Spec.MLMD != nil && dspa.Spec.MLMD.Envoy != nil {
+		exposures = append(exposures, dspa.Spec.MLMD.Envoy.Exposure)
+	}
+	if dspa.Spec.ObjectStorage != nil && dspa.Spec.ObjectStorage.Minio != nil {
+		exposures = append(exposures, dspa.Spec.ObjectStorage.Minio.Exposure)
+	}
+
+	var secretNames []string
+	for _, exposure := range exposures {
+		if exposure != nil && exposure.Route != nil && exposure.Route.TLS != nil && exposure.Route.TLS.CertificateSecretName != "" {
+			secretNames = append(secretNames, exposure.Route.TLS.CertificateSecretName)
+		}
+	}
+	return secretNames
+}
+
+// reconcileRouteCertificateReader lets the router read the certificate Secrets of the Routes of the DSPA, which the
+// Routes reference through their externalCertificate
+func (r *DSPAReconciler) reconcileRouteCertificateReader(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	if !r.ClusterCapabilities.Routes {
+		return nil
+	}
+	params.RouteCertificateSecretNames = routeCertificateSecretNames(dsp)
+	if len(params.RouteCertificateSecretNames) > 0 {
+		return r.ApplyDir(dsp, params, routeCertificateReaderTemplatesDir)
+	}
+	namespacedName := types.NamespacedName{Name: routeCertificateReaderNamePrefix + dsp.Name, Namespace: dsp.Namespace}
+	if err := r.DeleteResourceIfItExists(ctx, &rbacv1.RoleBinding{}, namespacedName); err != nil {
+		return err
+	}
+	return r.DeleteResourceIfItExists(ctx, &rbacv1.Role{}, namespacedName)
+}
+
+// dspasWithRouteCertificateSecret returns the DSPAs whose Routes use a certificate Secret, the CA certificate is copied
+// to the Routes so they must be reconciled when the Secret changes
+func (r *DSPAReconciler) dspasWithRouteCertificateSecret(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+	log := r.Log.WithValues("namespace", secret.Namespace)
+
+	var dspaList dspav1.DataSciencePipelinesApplicationList
+	if err := r.List(ctx, &dspaList, client.InNamespace(secret.Namespace)); err != nil {
+		log.Error(err, "unable to list DSPA's when attempting to handle Secret event.")
+		return nil
+	}
+
+	var reconcileRequests []reconcile.Request
+	for _, dspa := range dspaList.Items {
+		if !util.DSPAWithSupportedDSPVersion(&dspa) {
+			continue
+		}
+		for _, secretName := range routeCertificateSecretNames(&dspa) {
+			if secretName == secret.Name {
+				namespacedName := types.NamespacedName{Name: dspa.Name, Namespace: dspa.Namespace}
+				reconcileRequests = append(reconcileRequests, reconcile.Request{NamespacedName: namespacedName})
+				log.V(1).Info(fmt.Sprintf("Reconcile event triggered by change on Route certificate Secret: %s", secret.Name))
+				break
+			}
+		}
+	}
+	return reconcileRequests
+}
+
+// newHTTPRoute returns an empty Gateway API HTTPRoute. The Gateway API types are not part of the operator scheme
+// since the Gateway API is optional.
+func newHTTPRoute() *unstructured.Unstructured {
+	httpRoute := &unstructured.Unstructured{}
+	httpRoute.SetGroupVersionKind(HTTPRouteGVK)
+	return httpRoute
+}
+
+// getExternalUrl returns the URL of the resource exposing a component, whichever of the served exposure types it uses
+func (r *DSPAReconciler) getExternalUrl(ctx context.Context, name, namespace string) (string, error) {
+	if r.ClusterCapabilities.Routes {
+		url, err := util.GetRouteHostname(ctx, name, namespace, r.Client)
+		if err != nil || url != "" {
+			return url, err
+		}
+	}
+	if r.ClusterCapabilities.Ingresses {
+		url, err := util.GetIngressHostname(ctx, name, namespace, r.Client)
+		if err != nil || url != "" {
+			return url, err
+		}
+	}
+	if r.ClusterCapabilities.HTTPRoutes {
+		httpRoute := newHTTPRoute()
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, httpRoute)
+		if err != nil {
+			if apierrs.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		// The Gateway terminates TLS, the scheme of its listener is not known from the HTTPRoute
+		hostnames, _, err := unstructured.NestedStringSlice(httpRoute.Object, "spec", "hostnames")
+		if err != nil {
+			return "", err
+		}
+		if len(hostnames) > 0 {
+			return "https://" + hostnames[0], nil
+		}
+	}
+	return "", nil
+}
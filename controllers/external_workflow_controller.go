@@ -0,0 +1,305 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/dspastatus"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	argoWorkflowsAPIVersion = "v1alpha1"
+	// argoWorkflowControllerName is the name of the workflow controller in the Argo Workflows install manifests and
+	// Helm chart
+	argoWorkflowControllerName          = "workflow-controller"
+	argoWorkflowControllerConfigMapName = "workflow-controller-configmap"
+)
+
+var CustomResourceDefinitionGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// argoWorkflowsCRDs are the Argo Workflows CRDs the pipelines of a DSPA use
+var argoWorkflowsCRDs = []string{"workflows.argoproj.io", "workflowtaskresults.argoproj.io"}
+
+// argoWorkflowsVersionPattern matches the minor version of an Argo Workflows release, e.g. v3.6.12 or 3.6.12-upstream
+var argoWorkflowsVersionPattern = regexp.MustCompile(`^v?(\d+\.\d+)(\.\d+)?([-+].*)?$`)
+
+// externalWorkflowController is a workflow controller of an Argo Workflows installation of the cluster
+type externalWorkflowController struct {
+	Namespace string
+	Name      string
+	// Version is the release of Argo Workflows, empty when it cannot be determined
+	Version string
+	// ManagedNamespace is the namespace the controller runs the workflows of, empty when it runs the workflows of
+	// every namespace
+	ManagedNamespace string
+	// InstanceID restricts the controller to the workflows labeled with this instanceID when set
+	InstanceID string
+}
+
+func (c externalWorkflowController) String() string {
+	return c.Namespace + "/" + c.Name
+}
+
+func (c externalWorkflowController) manages(namespace string) bool {
+	return c.ManagedNamespace == "" || c.ManagedNamespace == namespace
+}
+
+// usesExternalWorkflowController reports whether the pipelines of the DSPA run with an external Argo Workflows
+// installation
+func usesExternalWorkflowController(dsp *dspav1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.WorkflowController != nil && dsp.Spec.WorkflowController.Mode == dspav1.WorkflowControllerModeExternal
+}
+
+// isArgoWorkflowController reports whether the Deployment is a workflow controller not deployed for a DSPA
+func isArgoWorkflowController(deployment *appsv1.Deployment) bool {
+	if deployment.Labels[config.DSPComponentk8sLabel] == config.DSPComponentk8sLabelValue {
+		return false
+	}
+	podLabels := deployment.Spec.Template.Labels
+	return podLabels["app"] == argoWorkflowControllerName ||
+		podLabels["app.kubernetes.io/component"] == argoWorkflowControllerName
+}
+
+// CheckExternalWorkflowController detects the Argo Workflows installation running the workflows of the DSPA and
+// checks it is compatible with the DSP version. The returned ExternalWorkflowControllerCompatible condition is Unknown
+// when the version of the workflow controller cannot be determined.
+func (r *DSPAReconciler) CheckExternalWorkflowController(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (metav1.Condition, error) {
+
+	notCompatible := func(reason, format string, args ...interface{}) (metav1.Condition, error) {
+		return dspastatus.BuildFalseCondition(config.ExternalWorkflowControllerCompatible, reason,
+			fmt.Sprintf(format, args...)), nil
+	}
+
+	for _, crdName := range argoWorkflowsCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(CustomResourceDefinitionGVK)
+		err := r.APIReader.Get(ctx, types.NamespacedName{Name: crdName}, crd)
+		if apierrs.IsNotFound(err) {
+			return notCompatible(config.ArgoWorkflowsNotFound, "The %s CRD of Argo Workflows is not installed", crdName)
+		} else if err != nil {
+			return metav1.Condition{}, err
+		}
+		if !servesCRDVersion(crd, argoWorkflowsAPIVersion) {
+			return notCompatible(config.ArgoWorkflowsIncompatible, "The %s CRD does not serve the %s version",
+				crdName, argoWorkflowsAPIVersion)
+		}
+	}
+
+	controllers, err := r.findExternalWorkflowControllers(ctx)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+	var managing, managingOtherInstances []externalWorkflowController
+	for _, controller := range controllers {
+		if !controller.manages(dsp.Namespace) {
+			continue
+		}
+		// The workflows of the DSPA do not carry an instanceID
+		if controller.InstanceID == "" {
+			managing = append(managing, controller)
+		} else {
+			managingOtherInstances = append(managingOtherInstances, controller)
+		}
+	}
+	switch {
+	case len(managing) == 0 && len(managingOtherInstances) > 0:
+		return notCompatible(config.ArgoWorkflowsIncompatible,
+			"The Argo Workflows controller %s only runs the workflows of the instanceID %s", managingOtherInstances[0],
+			managingOtherInstances[0].InstanceID)
+	case len(managing) == 0:
+		return notCompatible(config.ArgoWorkflowsNotFound, "No Argo Workflows controller runs the workflows of the "+
+			"namespace %s", dsp.Namespace)
+	case len(managing) > 1:
+		names := make([]string, 0, len(managing))
+		for _, controller := range managing {
+			names = append(names, controller.String())
+		}
+		return notCompatible(config.ArgoWorkflowsIncompatible, "The Argo Workflows controllers %s would all run the "+
+			"workflows of the namespace %s", strings.Join(names, ", "), dsp.Namespace)
+	}
+
+	controller := managing[0]
+	supportedVersions := config.SupportedArgoWorkflowsVersions[params.DSPVersion]
+	match := argoWorkflowsVersionPattern.FindStringSubmatch(controller.Version)
+	if match == nil {
+		condition := dspastatus.BuildUnknownCondition(config.ExternalWorkflowControllerCompatible)
+		condition.Reason = config.ArgoWorkflowsVersionUnknown
+		condition.Message = fmt.Sprintf("Unable to determine the version of the Argo Workflows controller %s, the "+
+			"versions compatible with DSP %s are %s", controller, params.DSPVersion, strings.Join(supportedVersions, ", "))
+		return condition, nil
+	}
+	if !slices.Contains(supportedVersions, match[1]) {
+		return notCompatible(config.ArgoWorkflowsIncompatible, "The Argo Workflows controller %s is version %s, the "+
+			"versions compatible with DSP %s are %s", controller, controller.Version, params.DSPVersion,
+			strings.Join(supportedVersions, ", "))
+	}
+	return dspastatus.BuildTrueCondition(config.ExternalWorkflowControllerCompatible,
+		fmt.Sprintf("The Argo Workflows controller %s version %s is compatible with DSP %s", controller,
+			controller.Version, params.DSPVersion)), nil
+}
+
+func servesCRDVersion(crd *unstructured.Unstructured, version string) bool {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		crdVersion, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(crdVersion, "name")
+		served, _, _ := unstructured.NestedBool(crdVersion, "served")
+		if name == version && served {
+			return true
+		}
+	}
+	return false
+}
+
+// findExternalWorkflowControllers returns the workflow controllers of the Argo Workflows installations of the cluster
+func (r *DSPAReconciler) findExternalWorkflowControllers(ctx context.Context) ([]externalWorkflowController, error) {
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments); err != nil {
+		return nil, err
+	}
+
+	var controllers []externalWorkflowController
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !isArgoWorkflowController(deployment) || len(deployment.Spec.Template.Spec.Containers) == 0 {
+			continue
+		}
+		container := deployment.Spec.Template.Spec.Containers[0]
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			if c.Name == argoWorkflowControllerName || c.Name == "controller" {
+				container = c
+			}
+		}
+
+		controller := externalWorkflowController{Namespace: deployment.Namespace, Name: deployment.Name}
+		controller.Version = deployment.Spec.Template.Labels["app.kubernetes.io/version"]
+		if controller.Version == "" {
+			controller.Version = imageTag(container.Image)
+		}
+
+		configMapName := argoWorkflowControllerConfigMapName
+		args := append(append([]string{}, container.Command...), container.Args...)
+		namespaced := false
+		for i, arg := range args {
+			name, value, hasValue := strings.Cut(arg, "=")
+			if !hasValue && i+1 < len(args) {
+				value = args[i+1]
+			}
+			switch name {
+			case "--namespaced":
+				namespaced = !hasValue || value == "true"
+			case "--managed-namespace":
+				controller.ManagedNamespace = value
+			case "--configmap":
+				configMapName = value
+			}
+		}
+		if !namespaced {
+			controller.ManagedNamespace = ""
+		} else if controller.ManagedNamespace == "" {
+			controller.ManagedNamespace = deployment.Namespace
+		}
+
+		instanceID, err := r.externalWorkflowControllerInstanceID(ctx, deployment.Namespace, configMapName)
+		if err != nil {
+			return nil, err
+		}
+		controller.InstanceID = instanceID
+		controllers = append(controllers, controller)
+	}
+	return controllers, nil
+}
+
+// externalWorkflowControllerInstanceID reads the instanceID from the configuration of a workflow controller
+func (r *DSPAReconciler) externalWorkflowControllerInstanceID(ctx context.Context, namespace, configMapName string) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: configMapName, Namespace: namespace}, configMap)
+	if apierrs.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var instanceID string
+	if legacyConfig, ok := configMap.Data[workflowControllerLegacyConfigKey]; ok {
+		var parsed struct {
+			InstanceID string `json:"instanceID"`
+		}
+		// An unparsable configuration is not loaded by the workflow controller either
+		_ = yaml.Unmarshal([]byte(legacyConfig), &parsed)
+		instanceID = parsed.InstanceID
+	}
+	if value, ok := configMap.Data["instanceID"]; ok {
+		instanceID = strings.TrimSpace(value)
+	}
+	return instanceID, nil
+}
+
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	lastSlash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > lastSlash {
+		return image[colon+1:]
+	}
+	return ""
+}
+
+// externalWorkflowControllerReconcileRequests reconciles the DSPAs in External mode when a workflow controller of
+// the cluster changes
+func (r *DSPAReconciler) externalWorkflowControllerReconcileRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	deployment, ok := o.(*appsv1.Deployment)
+	if !ok || !isArgoWorkflowController(deployment) {
+		return nil
+	}
+
+	var dspaList dspav1.DataSciencePipelinesApplicationList
+	if err := r.List(ctx, &dspaList); err != nil {
+		r.Log.Error(err, "unable to list DSPA's when attempting to handle Argo Workflows controller event.")
+		return nil
+	}
+	var reconcileRequests []reconcile.Request
+	for i := range dspaList.Items {
+		dspa := &dspaList.Items[i]
+		if usesExternalWorkflowController(dspa) && util.DSPAWithSupportedDSPVersion(dspa) {
+			reconcileRequests = append(reconcileRequests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: dspa.Name, Namespace: dspa.Namespace},
+			})
+		}
+	}
+	return reconcileRequests
+}
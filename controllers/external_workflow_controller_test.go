@@ -0,0 +1,170 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func externalWorkflowControllerDSPA() *dspav1.DataSciencePipelinesApplication {
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.DSPVersion = config.DSPV2VersionString
+	dspa.Spec.WorkflowController.Mode = dspav1.WorkflowControllerModeExternal
+	return dspa
+}
+
+func createArgoWorkflowsCRDs(t *testing.T, ctx context.Context, c client.Client) {
+	for _, name := range argoWorkflowsCRDs {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(CustomResourceDefinitionGVK)
+		crd.SetName(name)
+		require.NoError(t, unstructured.SetNestedSlice(crd.Object, []interface{}{
+			map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true},
+		}, "spec", "versions"))
+		require.NoError(t, c.Create(ctx, crd))
+	}
+}
+
+func argoWorkflowController(namespace, image string, args ...string) *appsv1.Deployment {
+	labels := map[string]string{"app": "workflow-controller"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow-controller", Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "workflow-controller", Image: image, Args: args},
+				}},
+			},
+		},
+	}
+}
+
+func TestCheckExternalWorkflowController(t *testing.T) {
+	dspa := externalWorkflowControllerDSPA()
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	condition, err := reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, config.ArgoWorkflowsNotFound, condition.Reason)
+	assert.Equal(t, "The workflows.argoproj.io CRD of Argo Workflows is not installed", condition.Message)
+
+	createArgoWorkflowsCRDs(t, ctx, reconciler.Client)
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, config.ArgoWorkflowsNotFound, condition.Reason)
+
+	// A controller restricted to another namespace does not run the workflows of the DSPA
+	namespacedController := argoWorkflowController("argo", "quay.io/argoproj/workflow-controller:v3.6.12", "--namespaced")
+	require.NoError(t, reconciler.Client.Create(ctx, namespacedController))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, "No Argo Workflows controller runs the workflows of the namespace testnamespace", condition.Message)
+
+	namespacedController.Spec.Template.Spec.Containers[0].Args = []string{"--namespaced", "--managed-namespace", "testnamespace"}
+	require.NoError(t, reconciler.Client.Update(ctx, namespacedController))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "The Argo Workflows controller argo/workflow-controller version v3.6.12 is compatible with DSP v2", condition.Message)
+
+	// The workflows of the DSPA do not carry an instanceID
+	instanceConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow-controller-configmap", Namespace: "argo"},
+		Data:       map[string]string{"instanceID": "team-a"},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, instanceConfig))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, config.ArgoWorkflowsIncompatible, condition.Reason)
+	assert.Equal(t, "The Argo Workflows controller argo/workflow-controller only runs the workflows of the instanceID team-a", condition.Message)
+	require.NoError(t, reconciler.Client.Delete(ctx, instanceConfig))
+
+	// Two controllers would run the same workflows
+	clusterController := argoWorkflowController("argo-cluster", "quay.io/argoproj/workflow-controller:v3.6.12")
+	require.NoError(t, reconciler.Client.Create(ctx, clusterController))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, config.ArgoWorkflowsIncompatible, condition.Reason)
+	require.NoError(t, reconciler.Client.Delete(ctx, namespacedController))
+
+	clusterController.Spec.Template.Spec.Containers[0].Image = "quay.io/argoproj/workflow-controller:v3.4.17"
+	require.NoError(t, reconciler.Client.Update(ctx, clusterController))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, config.ArgoWorkflowsIncompatible, condition.Reason)
+	assert.Equal(t, "The Argo Workflows controller argo-cluster/workflow-controller is version v3.4.17, the versions compatible with DSP v2 are 3.5, 3.6", condition.Message)
+
+	clusterController.Spec.Template.Spec.Containers[0].Image = "quay.io/argoproj/workflow-controller@sha256:0123456789abcdef"
+	require.NoError(t, reconciler.Client.Update(ctx, clusterController))
+	condition, err = reconciler.CheckExternalWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, config.ArgoWorkflowsVersionUnknown, condition.Reason)
+}
+
+func TestExternalModeRemovesWorkflowController(t *testing.T) {
+	dspa := workflowControllerConfigDSPA()
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	require.True(t, workflowControllerEnabled)
+
+	dspa.Spec.WorkflowController.Mode = dspav1.WorkflowControllerModeExternal
+	workflowControllerEnabled, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.False(t, workflowControllerEnabled)
+	created, err := reconciler.IsResourceCreated(ctx, &appsv1.Deployment{}, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestExternalWorkflowControllerReconcileRequests(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	externalDSPA := externalWorkflowControllerDSPA()
+	require.NoError(t, reconciler.Client.Create(ctx, externalDSPA))
+	managedDSPA := workflowControllerConfigDSPA()
+	managedDSPA.Name = "manageddspa"
+	managedDSPA.Spec.DSPVersion = config.DSPV2VersionString
+	require.NoError(t, reconciler.Client.Create(ctx, managedDSPA))
+
+	requests := reconciler.externalWorkflowControllerReconcileRequests(ctx,
+		argoWorkflowController("argo", "quay.io/argoproj/workflow-controller:v3.6.12"))
+	require.Len(t, requests, 1)
+	assert.Equal(t, "testdspa", requests[0].Name)
+
+	// The workflow controllers deployed for the DSPAs are ignored
+	managedController := argoWorkflowController("testnamespace", "quay.io/argoproj/workflow-controller:v3.6.12")
+	managedController.Labels = map[string]string{"component": "data-science-pipelines"}
+	assert.Empty(t, reconciler.externalWorkflowControllerReconcileRequests(ctx, managedController))
+}
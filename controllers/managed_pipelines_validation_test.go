@@ -337,6 +337,7 @@ func newAllReadyStatus(t *testing.T) dspastatus.DSPAStatus {
 	status.SetPersistenceAgentStatus(dspastatus.BuildTrueCondition(config.PersistenceAgentReady, "ready"))
 	status.SetScheduledWorkflowStatus(dspastatus.BuildTrueCondition(config.ScheduledWorkflowReady, "ready"))
 	status.SetWorkflowControllerReady()
+	status.SetExternalWorkflowControllerCompatibleNotApplicable()
 	status.SetMLMDProxyStatus(dspastatus.BuildTrueCondition(config.MLMDProxyReady, "ready"))
 	status.SetWebhookReady()
 	return status
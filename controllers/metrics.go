@@ -105,6 +105,16 @@ var (
 			"reason",
 		},
 	)
+	DatabaseBackupHealthyMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_database_backup_healthy",
+			Help: "Data Science Pipelines Application - Database Backup Health Status",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
 	CrReadyMetric = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "data_science_pipelines_application_ready",
@@ -127,6 +137,7 @@ var (
 		ScheduledWorkflowReadyMetric,
 		WorkflowControllerReadyMetric,
 		MLMDProxyReadyMetric,
+		DatabaseBackupHealthyMetric,
 		CrReadyMetric,
 	}
 
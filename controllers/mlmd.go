@@ -23,11 +23,19 @@ import (
 
 const (
 	mlmdTemplatesDir                   = "ml-metadata"
-	mlmdEnvoyRoute                     = mlmdTemplatesDir + "/route/metadata-envoy.route.yaml.tmpl"
 	mlmdProxyDefaultResourceNamePrefix = "ds-pipeline-scheduledworkflow-"
 	mlmdGrpcService                    = "grpc-service"
 )
 
+// The resources exposing the MLMD Envoy are deployed conditionally
+// as such they are handled separately
+var mlmdEnvoyExposureTemplates = exposureTemplates{
+	route:                mlmdTemplatesDir + "/route/metadata-envoy.route.yaml.tmpl",
+	ingress:              mlmdTemplatesDir + "/route/metadata-envoy.ingress.yaml.tmpl",
+	httpRoute:            mlmdTemplatesDir + "/route/metadata-envoy.httproute.yaml.tmpl",
+	routeTLSTerminations: []dspav1.RouteTLSTermination{dspav1.RouteTLSTerminationReencrypt, dspav1.RouteTLSTerminationPassthrough},
+}
+
 func (r *DSPAReconciler) ReconcileMLMD(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
 	params *DSPAParams) error {
 
@@ -40,12 +48,22 @@ func (r *DSPAReconciler) ReconcileMLMD(ctx context.Context, dsp *dspav1.DataScie
 
 	log.Info("Applying ML-Metadata (MLMD) Resources")
 
-	// We need to create the service first so OpenShift creates the certificate that we'll use later.
+	// We need to create the service first so OpenShift creates the certificate that we'll use later, unless the
+	// certificate is issued by cert-manager.
 	err := r.ApplyDir(dsp, params, mlmdTemplatesDir+"/"+mlmdGrpcService)
 	if err != nil {
 		return err
 	}
 
+	err = r.reconcileCertificate(ctx, dsp, params, mlmdGrpcCertificate, mlmdGrpcCertificateSecretNamePrefix+dsp.Name, params.PodToPodTLS)
+	if err != nil {
+		return err
+	}
+	err = r.reconcileCertificate(ctx, dsp, params, mlmdEnvoyCertificate, mlmdEnvoyCertificateSecretNamePrefix+dsp.Name, true)
+	if err != nil {
+		return err
+	}
+
 	if params.PodToPodTLS {
 		var certificatesExist bool
 		certificatesExist, err = params.LoadMlmdCertificates(ctx, r.Client)
@@ -58,16 +76,31 @@ func (r *DSPAReconciler) ReconcileMLMD(ctx context.Context, dsp *dspav1.DataScie
 		}
 	}
 
+	var envoyExposure *dspav1.Exposure
+	deployEnvoyRoute := true
+	if dsp.Spec.MLMD != nil && dsp.Spec.MLMD.Envoy != nil {
+		envoyExposure = dsp.Spec.MLMD.Envoy.Exposure
+		deployEnvoyRoute = dsp.Spec.MLMD.Envoy.DeployRoute
+	}
+	params.MlmdEnvoyExposure, err = r.resolveExposure(envoyExposure, deployEnvoyRoute)
+	if err != nil {
+		return err
+	}
+	// The MLMD Envoy is exposed through the kube-rbac-proxy deployed with deployRoute
+	if params.MlmdEnvoyExposure.Type != dspav1.ExposureNone && params.MLMD != nil && params.MLMD.Envoy != nil {
+		params.MLMD.Envoy.DeployRoute = true
+	}
+
 	err = r.ApplyDir(dsp, params, mlmdTemplatesDir)
 	if err != nil {
 		return err
 	}
 
-	if dsp.Spec.MLMD == nil || dsp.Spec.MLMD.Envoy == nil || dsp.Spec.MLMD.Envoy.DeployRoute {
-		err = r.Apply(dsp, params, mlmdEnvoyRoute)
-		if err != nil {
-			return err
-		}
+	exposureTemplates := mlmdEnvoyExposureTemplates
+	exposureTemplates.name = "ds-pipeline-md-" + dsp.Name
+	err = r.reconcileExposure(ctx, dsp, params, &params.MlmdEnvoyExposure, exposureTemplates)
+	if err != nil {
+		return err
 	}
 
 	log.Info("Finished applying MLMD Resources")
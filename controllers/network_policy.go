@@ -0,0 +1,309 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	egressNetworkPolicyTemplate   = "common/egress/networkpolicy.yaml.tmpl"
+	egressNetworkPolicyNamePrefix = "ds-pipelines-egress-"
+	postgreSQLDefaultPort         = "5432"
+	// KubernetesServiceName is the Service of the Kubernetes API server, whose EndpointSlices are watched for the
+	// egress NetworkPolicy
+	KubernetesServiceName = "kubernetes"
+)
+
+type NetworkPolicyParams struct {
+	// AdditionalIngressPeers are the JSON encoded peers allowed by the ingress rules of the NetworkPolicies
+	AdditionalIngressPeers []string
+	// EgressRestricted deploys the egress NetworkPolicy, which allows the JSON encoded EgressRules on top of the
+	// cluster DNS and the pods of the namespace
+	EgressRestricted bool
+	EgressRules      []string
+}
+
+// SetupNetworkPolicy resolves the additional peers of the NetworkPolicies and, when the egress is restricted, the
+// egress rules to the Kubernetes API server, database and object storage endpoints. It must run after the database
+// and object storage connections are resolved.
+func (p *DSPAParams) SetupNetworkPolicy(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication, client client.Client) error {
+	p.NetworkPolicy = NetworkPolicyParams{}
+	networkPolicy := dsp.Spec.NetworkPolicy
+	if networkPolicy == nil {
+		return nil
+	}
+
+	for _, peer := range networkPolicy.AdditionalIngressPeers {
+		encoded, err := json.Marshal(peer)
+		if err != nil {
+			return err
+		}
+		p.NetworkPolicy.AdditionalIngressPeers = append(p.NetworkPolicy.AdditionalIngressPeers, string(encoded))
+	}
+
+	if networkPolicy.EgressMode != dspav1.NetworkPolicyEgressRestricted {
+		return nil
+	}
+	p.NetworkPolicy.EgressRestricted = true
+
+	apiServerRule, err := kubernetesAPIServerEgressRule(ctx, client)
+	if err != nil {
+		return err
+	}
+	rules := []networkingv1.NetworkPolicyEgressRule{apiServerRule}
+	// The operator deployed MariaDB and Minio are reached within the namespace
+	peers := networkPolicy.AdditionalEgressPeers
+	if p.UsingExternalDB(dsp) {
+		defaultPort := config.MariaDBHostPort
+		if p.UsingPostgreSQL() {
+			defaultPort = postgreSQLDefaultPort
+		}
+		rule, err := externalEgressRule(p.DBConnection.Host, p.DBConnection.Port, defaultPort, peers)
+		if err != nil {
+			return fmt.Errorf("invalid database endpoint for the networkPolicy egress rules: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if p.UsingExternalStorage(dsp) {
+		rule, err := externalEgressRule(p.ObjectStorageConnection.Host, p.ObjectStorageConnection.Port,
+			schemeDefaultPort(p.ObjectStorageConnection.Scheme), peers)
+		if err != nil {
+			return fmt.Errorf("invalid object storage endpoint for the networkPolicy egress rules: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	for _, store := range p.ObjectStorageConnection.AdditionalStores {
+		rule, err := externalEgressRule(store.Host, store.Port, schemeDefaultPort(store.Scheme), peers)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint of the additionalStore [%s] for the networkPolicy egress rules: %w",
+				store.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	if p.MLflowEndpoint != "" {
+		rule, err := p.mlflowEgressRule(ctx, client, peers)
+		if err != nil {
+			return fmt.Errorf("invalid MLflow endpoint for the networkPolicy egress rules: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	// The credentials exchanged for tokens are verified by endpoints outside of the cluster, which a NetworkPolicy
+	// cannot select by their DNS names
+	if len(peers) == 0 {
+		if p.UsingWebIdentityStorageCredentials(dsp) {
+			return errors.New("the WebIdentity credentialsMode exchanges the service account token with the AWS STS " +
+				"endpoint, additionalEgressPeers must select its addresses")
+		}
+		if p.UsingExternalStorage(dsp) && p.ObjectStorageConnection.Provider == config.ObjectStorageProviderGCS {
+			return errors.New("the gcs provider requests OAuth tokens from oauth2.googleapis.com, additionalEgressPeers " +
+				"must select its addresses")
+		}
+	} else {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	for _, rule := range rules {
+		encoded, err := json.Marshal(rule)
+		if err != nil {
+			return err
+		}
+		p.NetworkPolicy.EgressRules = append(p.NetworkPolicy.EgressRules, string(encoded))
+	}
+	return nil
+}
+
+// kubernetesAPIServerEgressRule allows the egress traffic to the Kubernetes API server. A NetworkPolicy applies to the
+// traffic after the kubernetes Service is resolved, so the rule selects the addresses and ports of its endpoints.
+func kubernetesAPIServerEgressRule(ctx context.Context, c client.Client) (networkingv1.NetworkPolicyEgressRule, error) {
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+	err := c.List(ctx, endpointSlices, client.InNamespace(metav1.NamespaceDefault),
+		client.MatchingLabels{discoveryv1.LabelServiceName: KubernetesServiceName})
+	if err != nil {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("could not get the Kubernetes API server endpoints for the networkPolicy egress rules: %w", err)
+	}
+
+	rule := networkingv1.NetworkPolicyEgressRule{}
+	seenPorts := map[int32]bool{}
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil || seenPorts[*port.Port] {
+				continue
+			}
+			seenPorts[*port.Port] = true
+			protocol := corev1.ProtocolTCP
+			networkPolicyPort := intstr.FromInt32(*port.Port)
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &networkPolicyPort})
+		}
+		for _, endpoint := range endpointSlice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				if ip := net.ParseIP(address); ip != nil {
+					rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: hostCIDR(ip)}})
+				}
+			}
+		}
+	}
+	if len(rule.To) == 0 || len(rule.Ports) == 0 {
+		return networkingv1.NetworkPolicyEgressRule{}, errors.New("no Kubernetes API server endpoints found for the networkPolicy egress rules")
+	}
+	return rule, nil
+}
+
+// mlflowEgressRule allows the egress traffic to the MLflow tracking server. When the endpoint is a Service of the
+// cluster, the rule selects the pods of the Service on its target port, as a NetworkPolicy applies to the traffic
+// after the Service is resolved. Other endpoints are allowed like the external database and object storage.
+func (p *DSPAParams) mlflowEgressRule(ctx context.Context, c client.Client, peers []networkingv1.NetworkPolicyPeer) (networkingv1.NetworkPolicyEgressRule, error) {
+	endpoint, err := url.Parse(p.MLflowEndpoint)
+	if err != nil {
+		return networkingv1.NetworkPolicyEgressRule{}, err
+	}
+	port := endpoint.Port()
+	if port == "" {
+		port = schemeDefaultPort(endpoint.Scheme)
+	}
+	// Services are resolved as <name>.<namespace>.svc[.<cluster domain>]
+	hostLabels := strings.Split(endpoint.Hostname(), ".")
+	if len(hostLabels) < 3 || hostLabels[2] != "svc" {
+		return externalEgressRule(endpoint.Hostname(), port, "", peers)
+	}
+
+	namespacedName := types.NamespacedName{Name: hostLabels[0], Namespace: hostLabels[1]}
+	service := &corev1.Service{}
+	if p.GetService != nil {
+		service, err = p.GetService(ctx, namespacedName)
+	} else {
+		err = c.Get(ctx, namespacedName, service)
+	}
+	if err != nil {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("could not get the Service %s: %w", namespacedName, err)
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if strconv.Itoa(int(servicePort.Port)) != port {
+			continue
+		}
+		protocol := corev1.ProtocolTCP
+		targetPort := servicePort.TargetPort
+		if targetPort.IntVal == 0 && targetPort.StrVal == "" {
+			targetPort = intstr.FromInt32(servicePort.Port)
+		}
+		return networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &targetPort}},
+			To: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: namespacedName.Namespace},
+				},
+				PodSelector: &metav1.LabelSelector{MatchLabels: service.Spec.Selector},
+			}},
+		}, nil
+	}
+	return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("the Service %s has no port %s", namespacedName, port)
+}
+
+// externalEgressRule allows the egress traffic to an endpoint outside of the namespace. A NetworkPolicy cannot select
+// a DNS name, so the port of a host that is not an IP address is only allowed to the additional egress peers, which
+// must select its addresses.
+func externalEgressRule(host, port, defaultPort string, peers []networkingv1.NetworkPolicyPeer) (networkingv1.NetworkPolicyEgressRule, error) {
+	if port == "" {
+		port = defaultPort
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("invalid port %q", port)
+	}
+	protocol := corev1.ProtocolTCP
+	networkPolicyPort := intstr.FromInt32(int32(portNumber))
+	rule := networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &networkPolicyPort}},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		rule.To = []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: hostCIDR(ip)}}}
+	} else if len(peers) > 0 {
+		rule.To = peers
+	} else {
+		return networkingv1.NetworkPolicyEgressRule{}, fmt.Errorf("host %q is not an IP address, additionalEgressPeers must select its addresses", host)
+	}
+	return rule, nil
+}
+
+func hostCIDR(ip net.IP) string {
+	if ip.To4() == nil {
+		return ip.String() + "/128"
+	}
+	return ip.String() + "/32"
+}
+
+// kubernetesAPIServerEndpointsReconcileRequests reconciles the DSPAs restricting the egress traffic when the endpoints
+// of the Kubernetes API server change, so that their egress rule follows the API server addresses
+func (r *DSPAReconciler) kubernetesAPIServerEndpointsReconcileRequests(ctx context.Context, o client.Object) []reconcile.Request {
+	if o.GetNamespace() != metav1.NamespaceDefault || o.GetLabels()[discoveryv1.LabelServiceName] != KubernetesServiceName {
+		return nil
+	}
+
+	var dspaList dspav1.DataSciencePipelinesApplicationList
+	if err := r.List(ctx, &dspaList); err != nil {
+		r.Log.Error(err, "unable to list DSPA's when attempting to handle a Kubernetes API server endpoints event.")
+		return nil
+	}
+	var reconcileRequests []reconcile.Request
+	for _, dspa := range dspaList.Items {
+		networkPolicy := dspa.Spec.NetworkPolicy
+		if networkPolicy != nil && networkPolicy.EgressMode == dspav1.NetworkPolicyEgressRestricted &&
+			util.DSPAWithSupportedDSPVersion(&dspa) {
+			reconcileRequests = append(reconcileRequests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: dspa.Name, Namespace: dspa.Namespace},
+			})
+		}
+	}
+	return reconcileRequests
+}
+
+func schemeDefaultPort(scheme string) string {
+	if scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
+
+// reconcileEgressNetworkPolicy applies the egress NetworkPolicy when the egress is restricted, and removes it
+// otherwise
+func (r *DSPAReconciler) reconcileEgressNetworkPolicy(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	if params.NetworkPolicy.EgressRestricted {
+		return r.Apply(dsp, params, egressNetworkPolicyTemplate)
+	}
+	namespacedName := types.NamespacedName{Name: egressNetworkPolicyNamePrefix + dsp.Name, Namespace: dsp.Namespace}
+	return r.DeleteResourceIfItExists(ctx, &networkingv1.NetworkPolicy{}, namespacedName)
+}
@@ -0,0 +1,237 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// kubernetesEndpointSlice is the EndpointSlice of the kubernetes Service, selected by the egress NetworkPolicy
+func kubernetesEndpointSlice() *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "kubernetes"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}, {Addresses: []string{"10.0.0.2"}}},
+		Ports:       []discoveryv1.EndpointPort{{Port: util.Int32Pointer(6443)}},
+	}
+}
+
+func TestSetupNetworkPolicy(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			Database:      &dspav1.Database{ExternalDB: &dspav1.ExternalDB{}},
+			ObjectStorage: &dspav1.ObjectStorage{ExternalStorage: &dspav1.ExternalStorage{}},
+			NetworkPolicy: &dspav1.NetworkPolicyConfig{
+				AdditionalIngressPeers: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+				}},
+				EgressMode: dspav1.NetworkPolicyEgressRestricted,
+				AdditionalEgressPeers: []networkingv1.NetworkPolicyPeer{{
+					IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"},
+				}},
+			},
+		},
+	}
+	params := &DSPAParams{
+		DBConnection: DBConnection{Driver: config.PostgreSQLDriver, Host: "192.168.1.10"},
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:   "s3.amazonaws.com",
+			Scheme: "https",
+			AdditionalStores: []AdditionalObjectStoreConnection{
+				{Name: "archive", Host: "fd00::1", Port: "9000", Scheme: "http"},
+			},
+		},
+	}
+	// The Kubernetes API server endpoints are required
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		"no Kubernetes API server endpoints found for the networkPolicy egress rules")
+
+	require.NoError(t, reconciler.Create(ctx, kubernetesEndpointSlice()))
+	require.NoError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client))
+	assert.Equal(t, []string{`{"namespaceSelector":{"matchLabels":{"name":"monitoring"}}}`},
+		params.NetworkPolicy.AdditionalIngressPeers)
+	assert.True(t, params.NetworkPolicy.EgressRestricted)
+	assert.Equal(t, []string{
+		`{"ports":[{"protocol":"TCP","port":6443}],"to":[{"ipBlock":{"cidr":"10.0.0.1/32"}},{"ipBlock":{"cidr":"10.0.0.2/32"}}]}`,
+		`{"ports":[{"protocol":"TCP","port":5432}],"to":[{"ipBlock":{"cidr":"192.168.1.10/32"}}]}`,
+		// A NetworkPolicy cannot select a DNS name, the additional egress peers select its addresses
+		`{"ports":[{"protocol":"TCP","port":443}],"to":[{"ipBlock":{"cidr":"10.0.0.0/8"}}]}`,
+		`{"ports":[{"protocol":"TCP","port":9000}],"to":[{"ipBlock":{"cidr":"fd00::1/128"}}]}`,
+		`{"to":[{"ipBlock":{"cidr":"10.0.0.0/8"}}]}`,
+	}, params.NetworkPolicy.EgressRules)
+
+	// A DNS name is not allowed to every destination without additional egress peers
+	dspa.Spec.NetworkPolicy.AdditionalEgressPeers = nil
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		`invalid object storage endpoint for the networkPolicy egress rules: host "s3.amazonaws.com" is not an IP address, additionalEgressPeers must select its addresses`)
+
+	params.DBConnection.Port = "not-a-port"
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		`invalid database endpoint for the networkPolicy egress rules: invalid port "not-a-port"`)
+
+	dspa.Spec.NetworkPolicy.EgressMode = dspav1.NetworkPolicyEgressUnrestricted
+	require.NoError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client))
+	assert.False(t, params.NetworkPolicy.EgressRestricted)
+	assert.Empty(t, params.NetworkPolicy.EgressRules)
+}
+
+func TestSetupNetworkPolicyTokenEndpointsAndMLflow(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	require.NoError(t, reconciler.Create(ctx, kubernetesEndpointSlice()))
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			ObjectStorage: &dspav1.ObjectStorage{ExternalStorage: &dspav1.ExternalStorage{
+				CredentialsMode: config.ObjectStorageCredentialsModeWebIdentity,
+			}},
+			NetworkPolicy: &dspav1.NetworkPolicyConfig{EgressMode: dspav1.NetworkPolicyEgressRestricted},
+		},
+	}
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Provider: config.ObjectStorageProviderS3,
+			Host:     "192.168.1.20",
+			Scheme:   "https",
+		},
+		GetService: reconciler.getServiceUncached,
+	}
+	// The endpoints issuing the tokens are only reached through the additional egress peers
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		"the WebIdentity credentialsMode exchanges the service account token with the AWS STS endpoint, "+
+			"additionalEgressPeers must select its addresses")
+	dspa.Spec.ObjectStorage.ExternalStorage.CredentialsMode = ""
+	params.ObjectStorageConnection.Provider = config.ObjectStorageProviderGCS
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		"the gcs provider requests OAuth tokens from oauth2.googleapis.com, additionalEgressPeers must select its addresses")
+	params.ObjectStorageConnection.Provider = config.ObjectStorageProviderS3
+	require.NoError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client))
+	assert.Len(t, params.NetworkPolicy.EgressRules, 2)
+
+	// The MLflow Service is selected by the pods and target port behind it
+	params.MLflowEndpoint = "https://mlflow.mlflow-system.svc.cluster.local:8443"
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		`invalid MLflow endpoint for the networkPolicy egress rules: could not get the Service mlflow-system/mlflow: services "mlflow" not found`)
+	require.NoError(t, reconciler.Create(ctx, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "mlflow", Namespace: "mlflow-system"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "mlflow"},
+			Ports:    []corev1.ServicePort{{Name: "https", Port: 8443, TargetPort: intstr.FromString("https")}},
+		},
+	}))
+	require.NoError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client))
+	require.Len(t, params.NetworkPolicy.EgressRules, 3)
+	assert.Equal(t, `{"ports":[{"protocol":"TCP","port":"https"}],"to":[{"podSelector":{"matchLabels":{"app":"mlflow"}},`+
+		`"namespaceSelector":{"matchLabels":{"kubernetes.io/metadata.name":"mlflow-system"}}}]}`,
+		params.NetworkPolicy.EgressRules[2])
+
+	params.MLflowEndpoint = "https://mlflow.mlflow-system.svc:443"
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		"invalid MLflow endpoint for the networkPolicy egress rules: the Service mlflow-system/mlflow has no port 443")
+
+	// An MLflow endpoint outside of the cluster requires the additional egress peers
+	params.MLflowEndpoint = "https://mlflow.example.com"
+	assert.EqualError(t, params.SetupNetworkPolicy(ctx, dspa, reconciler.Client),
+		`invalid MLflow endpoint for the networkPolicy egress rules: host "mlflow.example.com" is not an IP address, additionalEgressPeers must select its addresses`)
+}
+
+func TestKubernetesAPIServerEndpointsReconcileRequests(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	restricted := testutil.CreateEmptyDSPA()
+	restricted.Name = "restricted"
+	restricted.Spec.DSPVersion = "v2"
+	restricted.Spec.NetworkPolicy = &dspav1.NetworkPolicyConfig{EgressMode: dspav1.NetworkPolicyEgressRestricted}
+	require.NoError(t, reconciler.Create(ctx, restricted))
+	unrestricted := testutil.CreateEmptyDSPA()
+	unrestricted.Name = "unrestricted"
+	unrestricted.Spec.DSPVersion = "v2"
+	require.NoError(t, reconciler.Create(ctx, unrestricted))
+
+	// Only the DSPAs restricting the egress traffic follow the Kubernetes API server endpoints
+	requests := reconciler.kubernetesAPIServerEndpointsReconcileRequests(ctx, kubernetesEndpointSlice())
+	assert.Equal(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: "restricted", Namespace: restricted.Namespace},
+	}}, requests)
+
+	otherEndpointSlice := kubernetesEndpointSlice()
+	otherEndpointSlice.Labels[discoveryv1.LabelServiceName] = "other"
+	assert.Empty(t, reconciler.kubernetesAPIServerEndpointsReconcileRequests(ctx, otherEndpointSlice))
+}
+
+func TestDeployNetworkPolicyCustomization(t *testing.T) {
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1.DSPASpec{
+			Database:      &dspav1.Database{MariaDB: &dspav1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1.ObjectStorage{Minio: &dspav1.Minio{Deploy: false, Image: "someimage"}},
+			NetworkPolicy: &dspav1.NetworkPolicyConfig{
+				AdditionalIngressPeers: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+				}},
+				EgressMode: dspav1.NetworkPolicyEgressRestricted,
+			},
+		},
+	}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, reconciler.Create(ctx, kubernetesEndpointSlice()))
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileCommon(ctx, dspa, params))
+
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	created, err := reconciler.IsResourceCreated(ctx, networkPolicy, "ds-pipelines-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	peers := networkPolicy.Spec.Ingress[1].From
+	assert.Equal(t, map[string]string{"name": "monitoring"}, peers[len(peers)-1].NamespaceSelector.MatchLabels)
+
+	egressNetworkPolicy := &networkingv1.NetworkPolicy{}
+	created, err = reconciler.IsResourceCreated(ctx, egressNetworkPolicy, "ds-pipelines-egress-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, egressNetworkPolicy.Spec.PolicyTypes)
+	assert.Equal(t, map[string]string{"component": "data-science-pipelines", "dspa": "testdspa"},
+		egressNetworkPolicy.Spec.PodSelector.MatchLabels)
+	// MariaDB is reached within the namespace, no rule is added for it
+	assert.Len(t, egressNetworkPolicy.Spec.Egress, 3)
+
+	// The egress NetworkPolicy is removed when the egress is not restricted anymore
+	dspa.Spec.NetworkPolicy.EgressMode = dspav1.NetworkPolicyEgressUnrestricted
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileCommon(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &networkingv1.NetworkPolicy{}, "ds-pipelines-egress-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+}
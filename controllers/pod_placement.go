@@ -0,0 +1,187 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodPlacementParams is the pod placement of a component. Each field is marshalled to JSON, which the deployment
+// templates render inline, and is empty when it is not set.
+type PodPlacementParams struct {
+	NodeSelector              string
+	Tolerations               string
+	Affinity                  string
+	TopologySpreadConstraints string
+}
+
+type ComponentPodPlacements struct {
+	APIServer          PodPlacementParams
+	PersistenceAgent   PodPlacementParams
+	ScheduledWorkflow  PodPlacementParams
+	WorkflowController PodPlacementParams
+	MariaDB            PodPlacementParams
+	Minio              PodPlacementParams
+	MlmdEnvoy          PodPlacementParams
+	MlmdGRPC           PodPlacementParams
+}
+
+// SetupPodPlacement resolves the pod placement of every component from spec.podPlacement and the podPlacement of
+// the component.
+func (p *DSPAParams) SetupPodPlacement(dsp *dspav1.DataSciencePipelinesApplication) error {
+	var apiServer, persistenceAgent, scheduledWorkflow, workflowController, mariaDB, minio, envoy, grpc *dspav1.PodPlacement
+	if dsp.Spec.APIServer != nil {
+		apiServer = dsp.Spec.APIServer.PodPlacement
+	}
+	if dsp.Spec.PersistenceAgent != nil {
+		persistenceAgent = dsp.Spec.PersistenceAgent.PodPlacement
+	}
+	if dsp.Spec.ScheduledWorkflow != nil {
+		scheduledWorkflow = dsp.Spec.ScheduledWorkflow.PodPlacement
+	}
+	if dsp.Spec.WorkflowController != nil {
+		workflowController = dsp.Spec.WorkflowController.PodPlacement
+	}
+	if dsp.Spec.Database != nil && dsp.Spec.Database.MariaDB != nil {
+		mariaDB = dsp.Spec.Database.MariaDB.PodPlacement
+	}
+	if dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.Minio != nil {
+		minio = dsp.Spec.ObjectStorage.Minio.PodPlacement
+	}
+	if dsp.Spec.MLMD != nil && dsp.Spec.MLMD.Envoy != nil {
+		envoy = dsp.Spec.MLMD.Envoy.PodPlacement
+	}
+	if dsp.Spec.MLMD != nil && dsp.Spec.MLMD.GRPC != nil {
+		grpc = dsp.Spec.MLMD.GRPC.PodPlacement
+	}
+
+	components := []struct {
+		name      string
+		appLabel  string
+		placement *dspav1.PodPlacement
+		params    *PodPlacementParams
+		// spreadReplicas spreads the replicas of a highly available component across nodes
+		spreadReplicas bool
+	}{
+		{"apiServer", p.APIServerDefaultResourceName, apiServer, &p.PodPlacement.APIServer, p.APIServerHighlyAvailable},
+		{"persistenceAgent", p.PersistentAgentDefaultResourceName, persistenceAgent, &p.PodPlacement.PersistenceAgent, false},
+		{"scheduledWorkflow", p.ScheduledWorkflowDefaultResourceName, scheduledWorkflow, &p.PodPlacement.ScheduledWorkflow, false},
+		{"workflowController", p.WorkflowControllerDefaultResourceName, workflowController, &p.PodPlacement.WorkflowController, false},
+		{"mariaDB", config.MariaDBHostPrefix + "-" + dsp.Name, mariaDB, &p.PodPlacement.MariaDB, false},
+		{"minio", config.MinioHostPrefix + "-" + dsp.Name, minio, &p.PodPlacement.Minio, false},
+		{"mlmd.envoy", "ds-pipeline-metadata-envoy-" + dsp.Name, envoy, &p.PodPlacement.MlmdEnvoy, false},
+		{"mlmd.grpc", "ds-pipeline-metadata-grpc-" + dsp.Name, grpc, &p.PodPlacement.MlmdGRPC, false},
+	}
+	for _, component := range components {
+		merged := mergePodPlacement(dsp.Spec.PodPlacement, component.placement)
+		if component.spreadReplicas {
+			setDefaultPodAntiAffinity(&merged, component.appLabel)
+		}
+		placement, err := newPodPlacementParams(merged, component.appLabel)
+		if err != nil {
+			return fmt.Errorf("invalid podPlacement for %s: %w", component.name, err)
+		}
+		*component.params = placement
+	}
+	return nil
+}
+
+// mergePodPlacement returns the placement of a component. Each field set on the component replaces the same field of
+// the DSPA default as a whole, e.g. a component nodeSelector is not merged with the default nodeSelector.
+func mergePodPlacement(dspaDefault, component *dspav1.PodPlacement) dspav1.PodPlacement {
+	var merged dspav1.PodPlacement
+	if dspaDefault != nil {
+		dspaDefault.DeepCopyInto(&merged)
+	}
+	if component == nil {
+		return merged
+	}
+	component = component.DeepCopy()
+	if component.NodeSelector != nil {
+		merged.NodeSelector = component.NodeSelector
+	}
+	if component.Tolerations != nil {
+		merged.Tolerations = component.Tolerations
+	}
+	if component.Affinity != nil {
+		merged.Affinity = component.Affinity
+	}
+	if component.TopologySpreadConstraints != nil {
+		merged.TopologySpreadConstraints = component.TopologySpreadConstraints
+	}
+	return merged
+}
+
+// setDefaultPodAntiAffinity prefers scheduling the replicas of a component on different nodes, unless the placement
+// already has a pod anti-affinity.
+func setDefaultPodAntiAffinity(placement *dspav1.PodPlacement, appLabel string) {
+	if placement.Affinity == nil {
+		placement.Affinity = &corev1.Affinity{}
+	}
+	if placement.Affinity.PodAntiAffinity != nil {
+		return
+	}
+	placement.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight: config.APIServerPodAntiAffinityWeight,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": appLabel}},
+				TopologyKey:   corev1.LabelHostname,
+			},
+		}},
+	}
+}
+
+// newPodPlacementParams marshals the placement for the deployment templates. Topology spread constraints without a
+// labelSelector select the pods of the component, identified by their app label.
+func newPodPlacementParams(placement dspav1.PodPlacement, appLabel string) (PodPlacementParams, error) {
+	for i := range placement.TopologySpreadConstraints {
+		if placement.TopologySpreadConstraints[i].LabelSelector == nil {
+			placement.TopologySpreadConstraints[i].LabelSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": appLabel},
+			}
+		}
+	}
+
+	var params PodPlacementParams
+	fields := []struct {
+		set   bool
+		value interface{}
+		out   *string
+	}{
+		{len(placement.NodeSelector) > 0, placement.NodeSelector, &params.NodeSelector},
+		{len(placement.Tolerations) > 0, placement.Tolerations, &params.Tolerations},
+		{placement.Affinity != nil, placement.Affinity, &params.Affinity},
+		{len(placement.TopologySpreadConstraints) > 0, placement.TopologySpreadConstraints, &params.TopologySpreadConstraints},
+	}
+	for _, field := range fields {
+		if !field.set {
+			continue
+		}
+		b, err := json.Marshal(field.value)
+		if err != nil {
+			return PodPlacementParams{}, err
+		}
+		*field.out = string(b)
+	}
+	return params, nil
+}
@@ -0,0 +1,127 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergePodPlacement(t *testing.T) {
+	infraToleration := corev1.Toleration{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	dspaDefault := &dspav1.PodPlacement{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:  []corev1.Toleration{infraToleration},
+	}
+
+	assert.Equal(t, dspav1.PodPlacement{}, mergePodPlacement(nil, nil))
+	assert.Equal(t, *dspaDefault, mergePodPlacement(dspaDefault, nil))
+
+	// Fields set on the component replace the default field as a whole
+	component := &dspav1.PodPlacement{NodeSelector: map[string]string{"disktype": "ssd"}}
+	merged := mergePodPlacement(dspaDefault, component)
+	assert.Equal(t, map[string]string{"disktype": "ssd"}, merged.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{infraToleration}, merged.Tolerations)
+
+	// An explicitly empty list clears the default
+	merged = mergePodPlacement(dspaDefault, &dspav1.PodPlacement{Tolerations: []corev1.Toleration{}})
+	assert.Empty(t, merged.Tolerations)
+	assert.Equal(t, dspaDefault.NodeSelector, merged.NodeSelector)
+
+	merged.NodeSelector["disktype"] = "hdd"
+	assert.NotContains(t, dspaDefault.NodeSelector, "disktype")
+}
+
+func TestDeployPodPlacement(t *testing.T) {
+	infraToleration := corev1.Toleration{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	zoneSpread := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+	}
+
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	dspa.Spec.PodPlacement = &dspav1.PodPlacement{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:  []corev1.Toleration{infraToleration},
+	}
+	dspa.Spec.APIServer.PodPlacement = &dspav1.PodPlacement{
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{zoneSpread},
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	dspa.Spec.Database.MariaDB = &dspav1.MariaDB{
+		Deploy:       true,
+		PodPlacement: &dspav1.PodPlacement{NodeSelector: map[string]string{"disktype": "ssd"}},
+	}
+	dspa.Spec.WorkflowController = &dspav1.WorkflowController{Deploy: true}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/infra": ""}, podSpec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{infraToleration}, podSpec.Tolerations)
+	assert.Equal(t, dspa.Spec.APIServer.PodPlacement.Affinity, podSpec.Affinity)
+	// Constraints without a labelSelector spread the pods of the component
+	expectedSpread := zoneSpread
+	expectedSpread.LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ds-pipeline-testdspa"}}
+	assert.Equal(t, []corev1.TopologySpreadConstraint{expectedSpread}, podSpec.TopologySpreadConstraints)
+
+	require.NoError(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, "mariadb-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	podSpec = deployment.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"disktype": "ssd"}, podSpec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{infraToleration}, podSpec.Tolerations)
+	assert.Nil(t, podSpec.Affinity)
+	assert.Empty(t, podSpec.TopologySpreadConstraints)
+
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, map[string]string{"node-role.kubernetes.io/infra": ""}, deployment.Spec.Template.Spec.NodeSelector)
+}
@@ -0,0 +1,221 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SetupPodTemplateOverrides validates the podTemplateOverrides of every component and indexes them by the name of the
+// component's Deployment.
+func (p *DSPAParams) SetupPodTemplateOverrides(dsp *dspav1.DataSciencePipelinesApplication) error {
+	var apiServer, persistenceAgent, scheduledWorkflow, workflowController, mariaDB, minio, envoy, grpc *dspav1.PodTemplateOverrides
+	if dsp.Spec.APIServer != nil {
+		apiServer = dsp.Spec.APIServer.PodTemplateOverrides
+	}
+	if dsp.Spec.PersistenceAgent != nil {
+		persistenceAgent = dsp.Spec.PersistenceAgent.PodTemplateOverrides
+	}
+	if dsp.Spec.ScheduledWorkflow != nil {
+		scheduledWorkflow = dsp.Spec.ScheduledWorkflow.PodTemplateOverrides
+	}
+	if dsp.Spec.WorkflowController != nil {
+		workflowController = dsp.Spec.WorkflowController.PodTemplateOverrides
+	}
+	if dsp.Spec.Database != nil && dsp.Spec.Database.MariaDB != nil {
+		mariaDB = dsp.Spec.Database.MariaDB.PodTemplateOverrides
+	}
+	if dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.Minio != nil {
+		minio = dsp.Spec.ObjectStorage.Minio.PodTemplateOverrides
+	}
+	if dsp.Spec.MLMD != nil && dsp.Spec.MLMD.Envoy != nil {
+		envoy = dsp.Spec.MLMD.Envoy.PodTemplateOverrides
+	}
+	if dsp.Spec.MLMD != nil && dsp.Spec.MLMD.GRPC != nil {
+		grpc = dsp.Spec.MLMD.GRPC.PodTemplateOverrides
+	}
+
+	components := []struct {
+		name           string
+		deploymentName string
+		overrides      *dspav1.PodTemplateOverrides
+	}{
+		{"apiServer", p.APIServerDefaultResourceName, apiServer},
+		{"persistenceAgent", p.PersistentAgentDefaultResourceName, persistenceAgent},
+		{"scheduledWorkflow", p.ScheduledWorkflowDefaultResourceName, scheduledWorkflow},
+		{"workflowController", p.WorkflowControllerDefaultResourceName, workflowController},
+		{"mariaDB", config.MariaDBHostPrefix + "-" + dsp.Name, mariaDB},
+		{"minio", config.MinioHostPrefix + "-" + dsp.Name, minio},
+		{"mlmd.envoy", "ds-pipeline-metadata-envoy-" + dsp.Name, envoy},
+		{"mlmd.grpc", "ds-pipeline-metadata-grpc-" + dsp.Name, grpc},
+	}
+	p.PodTemplateOverrides = map[string]*dspav1.PodTemplateOverrides{}
+	for _, component := range components {
+		if component.overrides == nil {
+			continue
+		}
+		if err := validatePodTemplateOverrides(component.overrides); err != nil {
+			return fmt.Errorf("invalid podTemplateOverrides for %s: %w", component.name, err)
+		}
+		p.PodTemplateOverrides[component.deploymentName] = component.overrides.DeepCopy()
+	}
+	return nil
+}
+
+// validatePodTemplateOverrides checks the overrides on their own, conflicts with the resources managed by the operator
+// are only known once the Deployment is rendered and are detected by podTemplateOverridesTransformer.
+func validatePodTemplateOverrides(overrides *dspav1.PodTemplateOverrides) error {
+	envNames := map[string]bool{}
+	for _, env := range overrides.Env {
+		if env.Name == "" {
+			return errors.New("env vars must have a name")
+		}
+		if envNames[env.Name] {
+			return fmt.Errorf("env var %s is set more than once", env.Name)
+		}
+		envNames[env.Name] = true
+	}
+	volumeNames := map[string]bool{}
+	for _, volume := range overrides.Volumes {
+		if volume.Name == "" {
+			return errors.New("volumes must have a name")
+		}
+		if volumeNames[volume.Name] {
+			return fmt.Errorf("volume %s is defined more than once", volume.Name)
+		}
+		volumeNames[volume.Name] = true
+	}
+	mountPaths := map[string]bool{}
+	for _, volumeMount := range overrides.VolumeMounts {
+		if volumeMount.Name == "" || volumeMount.MountPath == "" {
+			return errors.New("volumeMounts must have a name and a mountPath")
+		}
+		if mountPaths[volumeMount.MountPath] {
+			return fmt.Errorf("mountPath %s is used more than once", volumeMount.MountPath)
+		}
+		mountPaths[volumeMount.MountPath] = true
+	}
+	containerNames := map[string]bool{}
+	for _, container := range overrides.Containers {
+		if container.Name == "" || container.Image == "" {
+			return errors.New("containers must have a name and an image")
+		}
+		if containerNames[container.Name] {
+			return fmt.Errorf("container %s is defined more than once", container.Name)
+		}
+		containerNames[container.Name] = true
+	}
+	return nil
+}
+
+// podTemplateOverridesTransformer adds the podTemplateOverrides of a component to its rendered Deployment, the first
+// container of which is the main container of the component. Env vars, volumes, mount paths and containers rendered
+// by the operator cannot be overridden.
+func podTemplateOverridesTransformer(overridesByDeployment map[string]*dspav1.PodTemplateOverrides) mf.Transformer {
+	return func(mfObj *unstructured.Unstructured) error {
+		if mfObj.GetKind() != "Deployment" {
+			return nil
+		}
+		overrides, ok := overridesByDeployment[mfObj.GetName()]
+		if !ok {
+			return nil
+		}
+
+		deployment := &appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(mfObj.Object, deployment); err != nil {
+			return err
+		}
+		podSpec := &deployment.Spec.Template.Spec
+		if len(podSpec.Containers) == 0 {
+			return fmt.Errorf("deployment %s has no containers to apply the podTemplateOverrides to", mfObj.GetName())
+		}
+		main := podSpec.Containers[0]
+
+		for _, env := range overrides.Env {
+			for _, managed := range main.Env {
+				if managed.Name == env.Name {
+					return fmt.Errorf("podTemplateOverrides cannot override env var %s, it is managed by the operator", env.Name)
+				}
+			}
+		}
+		for _, volume := range overrides.Volumes {
+			for _, managed := range podSpec.Volumes {
+				if managed.Name == volume.Name {
+					return fmt.Errorf("podTemplateOverrides cannot override volume %s, it is managed by the operator", volume.Name)
+				}
+			}
+		}
+		for _, volumeMount := range overrides.VolumeMounts {
+			for _, managed := range main.VolumeMounts {
+				if managed.MountPath == volumeMount.MountPath {
+					return fmt.Errorf("podTemplateOverrides cannot override mountPath %s, it is managed by the operator", volumeMount.MountPath)
+				}
+			}
+		}
+		for _, container := range overrides.Containers {
+			for _, managed := range append(podSpec.InitContainers, podSpec.Containers...) {
+				if managed.Name == container.Name {
+					return fmt.Errorf("podTemplateOverrides cannot override container %s, it is managed by the operator", container.Name)
+				}
+			}
+		}
+
+		// The overrides are appended to the rendered object rather than to the typed Deployment, so that the applied
+		// manifest only changes by the overrides
+		overridesObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(overrides)
+		if err != nil {
+			return err
+		}
+		containers, _, err := unstructured.NestedSlice(mfObj.Object, "spec", "template", "spec", "containers")
+		if err != nil {
+			return err
+		}
+		mainContainer := containers[0].(map[string]interface{})
+		for _, field := range []string{"env", "envFrom", "volumeMounts"} {
+			if err := appendUnstructuredList(mainContainer, overridesObj[field], field); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(mfObj.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+			return err
+		}
+		if err := appendUnstructuredList(mfObj.Object, overridesObj["volumes"], "spec", "template", "spec", "volumes"); err != nil {
+			return err
+		}
+		return appendUnstructuredList(mfObj.Object, overridesObj["containers"], "spec", "template", "spec", "containers")
+	}
+}
+
+// appendUnstructuredList appends items, if any, to the list found at fields in obj
+func appendUnstructuredList(obj map[string]interface{}, items interface{}, fields ...string) error {
+	itemList, _ := items.([]interface{})
+	if len(itemList) == 0 {
+		return nil
+	}
+	list, _, err := unstructured.NestedSlice(obj, fields...)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(obj, append(list, itemList...), fields...)
+}
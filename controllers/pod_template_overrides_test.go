@@ -0,0 +1,148 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidatePodTemplateOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     dspav1.PodTemplateOverrides
+		expectedError string
+	}{
+		{
+			name: "valid overrides",
+			overrides: dspav1.PodTemplateOverrides{
+				Env:          []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel:4317"}},
+				Volumes:      []corev1.Volume{{Name: "corporate-ca"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "corporate-ca", MountPath: "/etc/pki/corporate"}},
+				Containers:   []corev1.Container{{Name: "log-shipper", Image: "log-shipper:latest"}},
+			},
+		},
+		{
+			name:          "duplicate env var",
+			overrides:     dspav1.PodTemplateOverrides{Env: []corev1.EnvVar{{Name: "FOO"}, {Name: "FOO"}}},
+			expectedError: "env var FOO is set more than once",
+		},
+		{
+			name:          "volume mount without mount path",
+			overrides:     dspav1.PodTemplateOverrides{VolumeMounts: []corev1.VolumeMount{{Name: "corporate-ca"}}},
+			expectedError: "volumeMounts must have a name and a mountPath",
+		},
+		{
+			name:          "container without image",
+			overrides:     dspav1.PodTemplateOverrides{Containers: []corev1.Container{{Name: "log-shipper"}}},
+			expectedError: "containers must have a name and an image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePodTemplateOverrides(&tt.overrides)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestDeployPodTemplateOverrides(t *testing.T) {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	dspa.Spec.APIServer.PodTemplateOverrides = &dspav1.PodTemplateOverrides{
+		Env: []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel:4317"}},
+		EnvFrom: []corev1.EnvFromSource{{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "otel-config"}},
+		}},
+		Volumes: []corev1.Volume{{
+			Name: "corporate-ca",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "corporate-ca"}},
+			},
+		}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "corporate-ca", MountPath: "/etc/pki/corporate", ReadOnly: true}},
+		Containers:   []corev1.Container{{Name: "log-shipper", Image: "log-shipper:latest"}},
+	}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+
+	apiServerContainer := getDSPipelineAPIServerContainer(deployment)
+	require.NotNil(t, apiServerContainer)
+	value, found := getEnvValue(t, apiServerContainer, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	assert.True(t, found)
+	assert.Equal(t, "http://otel:4317", value)
+	// The env vars managed by the operator are kept
+	_, found = getEnvValue(t, apiServerContainer, "POD_NAMESPACE")
+	assert.True(t, found)
+	assert.Contains(t, apiServerContainer.EnvFrom, dspa.Spec.APIServer.PodTemplateOverrides.EnvFrom[0])
+	assert.Contains(t, apiServerContainer.VolumeMounts, dspa.Spec.APIServer.PodTemplateOverrides.VolumeMounts[0])
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, dspa.Spec.APIServer.PodTemplateOverrides.Volumes[0])
+
+	podContainers := deployment.Spec.Template.Spec.Containers
+	assert.Equal(t, "log-shipper", podContainers[len(podContainers)-1].Name)
+	assert.Equal(t, "log-shipper:latest", podContainers[len(podContainers)-1].Image)
+}
+
+func TestPodTemplateOverridesCannotOverrideOperatorDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     dspav1.PodTemplateOverrides
+		expectedError string
+	}{
+		{
+			name:          "env var",
+			overrides:     dspav1.PodTemplateOverrides{Env: []corev1.EnvVar{{Name: "POD_NAMESPACE", Value: "other"}}},
+			expectedError: "podTemplateOverrides cannot override env var POD_NAMESPACE, it is managed by the operator",
+		},
+		{
+			name:          "container",
+			overrides:     dspav1.PodTemplateOverrides{Containers: []corev1.Container{{Name: "ds-pipeline-api-server", Image: "other"}}},
+			expectedError: "podTemplateOverrides cannot override container ds-pipeline-api-server, it is managed by the operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+			dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+			dspa.Spec.APIServer.PodTemplateOverrides = &tt.overrides
+
+			ctx, params, reconciler := CreateNewTestObjects()
+			require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+			err := reconciler.ReconcileAPIServer(ctx, dspa, params)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
@@ -17,28 +17,44 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
+	"strings"
 
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
 	"golang.org/x/net/http/httpproxy"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const storageSecret = "minio/generated-secret/secret.yaml.tmpl"
-const storageRoute = "minio/route.yaml.tmpl"
+
+// The resources exposing Minio are deployed conditionally
+// as such they are handled separately
+var minioExposureTemplates = exposureTemplates{
+	route:                "minio/route.yaml.tmpl",
+	ingress:              "minio/ingress.yaml.tmpl",
+	httpRoute:            "minio/httproute.yaml.tmpl",
+	routeTLSTerminations: []dspav1.RouteTLSTermination{dspav1.RouteTLSTerminationEdge},
+}
 
 var minioTemplates = []string{
 	"minio/default/deployment.yaml.tmpl",
@@ -46,7 +62,6 @@ var minioTemplates = []string{
 	"minio/default/service.yaml.tmpl",
 	"minio/default/service.minioservice.yaml.tmpl",
 	"minio/default/minio-sa.yaml.tmpl",
-	storageRoute,
 }
 
 func joinHostPort(host, port string) (string, error) {
@@ -77,6 +92,54 @@ func createCredentialProvidersChain(accessKey, secretKey string) *credentials.Cr
 	return credentials.New(&credentials.Chain{Providers: providers})
 }
 
+// createWebIdentityCredentials returns credentials that are exchanged with STS for temporary credentials of the
+// configured role, using a token requested for the API Server service account. This is the identity the pipeline
+// server assumes the role with, so the health check verifies the same trust relationship.
+func (r *DSPAReconciler) createWebIdentityCredentials(ctx context.Context, log logr.Logger, params *DSPAParams) (*credentials.Credentials, error) {
+	webIdentity := params.ObjectStorageConnection.WebIdentity
+
+	transport, err := getTransportWithProxyAndCACert(log, params.APICustomPemCerts, params.ProxyConfig, true)
+	if err != nil {
+		return nil, err
+	}
+
+	getWebIdentityToken := func() (*credentials.WebIdentityToken, error) {
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: params.APIServerDefaultResourceName, Namespace: params.Namespace},
+		}
+		expirationSeconds := int64(config.WebIdentityTokenExpirationSeconds)
+		tokenRequest := &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         []string{webIdentity.Audience},
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}
+		if err := r.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+			return nil, fmt.Errorf("failed to request a token for service account %s: %w", serviceAccount.Name, err)
+		}
+		return &credentials.WebIdentityToken{Token: tokenRequest.Status.Token, Expiry: int(expirationSeconds)}, nil
+	}
+
+	return credentials.NewSTSWebIdentity(getSTSEndpoint(params.ObjectStorageConnection.Region), getWebIdentityToken,
+		func(i *credentials.STSWebIdentity) {
+			i.Client = &http.Client{Transport: transport}
+			i.RoleARN = webIdentity.RoleARN
+		})
+}
+
+// getSTSEndpoint returns the regional AWS STS endpoint, matching the endpoint the AWS SDKs use in the DSP components
+// when AWS_REGION is set.
+func getSTSEndpoint(region string) string {
+	switch {
+	case region == "" || region == "auto":
+		return credentials.DefaultSTSRoleEndpoint
+	case strings.HasPrefix(region, "cn-"):
+		return "https://sts." + region + ".amazonaws.com.cn"
+	default:
+		return "https://sts." + region + ".amazonaws.com"
+	}
+}
+
 // addCustomCACerts adds custom CA certificates to a transport's TLS config
 func addCustomCACerts(transport *http.Transport, log logr.Logger, pemCerts [][]byte) error {
 	if len(pemCerts) == 0 {
@@ -141,16 +204,32 @@ func getTransportWithProxyAndCACert(log logr.Logger, pemCerts [][]byte, proxyCon
 	return transport, nil
 }
 
-var ConnectAndQueryObjStore = func(
-	ctx context.Context,
-	log logr.Logger,
-	endpoint, bucket string,
-	accesskey, secretkey []byte,
-	secure bool,
-	pemCerts [][]byte,
-	proxyConfig *dspav1.ProxyConfig,
-	objStoreConnectionTimeout time.Duration) (bool, error) {
-	cred := createCredentialProvidersChain(string(accesskey), string(secretkey))
+// ObjectStoreHealthCheckError is a failed object storage health check with a more specific ObjectStoreAvailable
+// condition reason than FailingToDeploy.
+type ObjectStoreHealthCheckError struct {
+	Reason string
+	Err    error
+}
+
+func (e *ObjectStoreHealthCheckError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ObjectStoreHealthCheckError) Unwrap() error {
+	return e.Err
+}
+
+// objectStoreHealthCheckReason returns the ObjectStoreAvailable condition reason of a failed health check.
+func objectStoreHealthCheckReason(err error) string {
+	var healthCheckErr *ObjectStoreHealthCheckError
+	if errors.As(err, &healthCheckErr) {
+		return healthCheckErr.Reason
+	}
+	return config.FailingToDeploy
+}
+
+func newObjStoreClient(log logr.Logger, endpoint string, cred *credentials.Credentials, secure bool, pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig) (*minio.Client, error) {
 
 	opts := &minio.Options{
 		Creds:  cred,
@@ -162,7 +241,7 @@ var ConnectAndQueryObjStore = func(
 		if err != nil {
 			errorMessage := "encountered error when processing custom ca bundle or proxy configuration"
 			log.Error(err, errorMessage)
-			return false, errors.New(errorMessage)
+			return nil, errors.New(errorMessage)
 		}
 		opts.Transport = tr
 	}
@@ -171,7 +250,24 @@ var ConnectAndQueryObjStore = func(
 	if err != nil {
 		errorMessage := fmt.Sprintf("Could not connect to object storage endpoint: %s", endpoint)
 		log.Error(err, errorMessage)
-		return false, errors.New(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+	return minioClient, nil
+}
+
+var ConnectAndQueryObjStore = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint, bucket string,
+	cred *credentials.Credentials,
+	secure bool,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
+
+	minioClient, err := newObjStoreClient(log, endpoint, cred, secure, pemCerts, proxyConfig)
+	if err != nil {
+		return false, err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
@@ -216,6 +312,112 @@ var ConnectAndQueryObjStore = func(
 	return true, nil
 }
 
+// ConnectAndProbeObjStore writes, reads back and deletes a probe object, verifying that the credentials have the
+// PutObject, GetObject and DeleteObject permissions pipeline runs need. Failures caused by missing permissions or a
+// missing bucket are returned as ObjectStoreHealthCheckError.
+var ConnectAndProbeObjStore = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint, bucket, objectName string,
+	cred *credentials.Credentials,
+	sse encrypt.ServerSide,
+	secure bool,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
+
+	minioClient, err := newObjStoreClient(log, endpoint, cred, secure, pemCerts, proxyConfig)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	content := []byte("Data Science Pipelines object storage health check")
+	_, err = minioClient.PutObject(ctx, bucket, objectName, bytes.NewReader(content), int64(len(content)),
+		minio.PutObjectOptions{ContentType: "text/plain", ServerSideEncryption: sse})
+	if err != nil {
+		return false, objStoreProbeError(log, endpoint, bucket, objectName, "write", config.MissingWritePermission, err)
+	}
+
+	object, err := minioClient.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	if err == nil {
+		var readContent []byte
+		readContent, err = io.ReadAll(object)
+		_ = object.Close()
+		if err == nil && !bytes.Equal(readContent, content) {
+			err = fmt.Errorf("read back %d bytes that do not match the %d bytes written", len(readContent), len(content))
+		}
+	}
+	if err != nil {
+		return false, objStoreProbeError(log, endpoint, bucket, objectName, "read", config.MissingReadPermission, err)
+	}
+
+	err = minioClient.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{})
+	if err != nil {
+		return false, objStoreProbeError(log, endpoint, bucket, objectName, "delete", config.MissingDeletePermission, err)
+	}
+
+	return true, nil
+}
+
+// objStoreProbeError describes a failed step of the read-write health check, using the reason of the step when the
+// object store denied the access.
+func objStoreProbeError(log logr.Logger, endpoint, bucket, objectName, operation, deniedReason string, err error) error {
+	if util.IsX509UnknownAuthorityError(err) {
+		errorMessage := "encountered x509 UnknownAuthorityError when connecting to ObjectStore: " +
+			"if using a TLS S3 connection with self-signed certs, you may specify a custom CABundle " +
+			"to mount on the DSP API Server via the DSPA cr under the spec.apiServer.cABundle field; if you have already " +
+			"provided a CABundle, verify the validity of the provided CABundle"
+		log.Info(errorMessage)
+		return errors.New(errorMessage)
+	}
+
+	code := minio.ToErrorResponse(err).Code
+	if strings.HasPrefix(code, "KMS.") || code == "InvalidEncryptionAlgorithmError" || code == "InvalidArgument" {
+		errorMessage := fmt.Sprintf("the object storage rejected the server-side encryption settings when trying to %s the object %s in bucket %s: %s",
+			operation, objectName, bucket, err.Error())
+		log.Info(errorMessage)
+		return &ObjectStoreHealthCheckError{Reason: config.EncryptionMisconfigured, Err: errors.New(errorMessage)}
+	}
+
+	switch code {
+	case "NoSuchBucket":
+		errorMessage := fmt.Sprintf("bucket %s does not exist in object storage (%s)", bucket, endpoint)
+		log.Info(errorMessage)
+		return &ObjectStoreHealthCheckError{Reason: config.BucketNotFound, Err: errors.New(errorMessage)}
+	case "AccessDenied":
+		errorMessage := fmt.Sprintf("the object storage credentials are not allowed to %s the object %s in bucket %s: %s",
+			operation, objectName, bucket, err.Error())
+		log.Info(errorMessage)
+		return &ObjectStoreHealthCheckError{Reason: deniedReason, Err: errors.New(errorMessage)}
+	}
+
+	errorMessage := fmt.Sprintf("Could not %s the object %s in (%s), Error: %s", operation, objectName, endpoint, err.Error())
+	log.Info(errorMessage)
+	return errors.New(errorMessage)
+}
+
+// getObjStoreServerSideEncryption returns the server-side encryption of the Argo artifact repository, or nil.
+func getObjStoreServerSideEncryption(params *DSPAParams) (encrypt.ServerSide, error) {
+	encryption := params.ObjectStorageConnection.Encryption
+	if encryption == nil {
+		return nil, nil
+	}
+	switch encryption.Type {
+	case config.ObjectStorageEncryptionSSEKMS:
+		return encrypt.NewSSEKMS(encryption.KMSKeyID, nil)
+	default:
+		return encrypt.NewSSE(), nil
+	}
+}
+
+// objStoreProbeObjectName returns the name of the read-write health check probe object under the base path.
+func objStoreProbeObjectName(params *DSPAParams) string {
+	return path.Join(params.ObjectStorageConnection.BasePath, config.ObjectStorageHealthCheckObjectPrefix+params.Name)
+}
+
 func (r *DSPAReconciler) isObjectStorageAccessible(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
 	params *DSPAParams) (bool, error) {
 	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
@@ -234,33 +436,163 @@ func (r *DSPAReconciler) isObjectStorageAccessible(ctx context.Context, dsp *dsp
 		return false, errors.New(errorMessage)
 	}
 
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+
+	log.V(1).Info(fmt.Sprintf("Object Store connection timeout: %s", objStoreConnectionTimeout))
+
+	var verified bool
+	switch params.ObjectStorageConnection.Provider {
+	case config.ObjectStorageProviderGCS:
+		verified, err = r.isGCSBucketAccessible(ctx, log, params, objStoreConnectionTimeout)
+		logObjectStorageHealthCheckResult(log, err)
+		return verified, err
+	case config.ObjectStorageProviderAzure:
+		verified, err = r.isAzureContainerAccessible(ctx, log, params, objStoreConnectionTimeout)
+		logObjectStorageHealthCheckResult(log, err)
+		return verified, err
+	}
+
+	cred, err := r.getObjStoreCredentials(ctx, log, params)
+	if err != nil {
+		return false, err
+	}
+
+	if params.ObjectStorageHealthCheckMode(dsp) == config.ObjectStorageHealthCheckModeReadWrite {
+		var sse encrypt.ServerSide
+		sse, err = getObjStoreServerSideEncryption(params)
+		if err != nil {
+			log.Error(err, "could not configure object storage server-side encryption")
+			return false, &ObjectStoreHealthCheckError{Reason: config.EncryptionMisconfigured, Err: err}
+		}
+		verified, err = ConnectAndProbeObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, objStoreProbeObjectName(params),
+			cred, sse, *params.ObjectStorageConnection.Secure, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+	} else {
+		verified, err = ConnectAndQueryObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, cred,
+			*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+	}
+
+	logObjectStorageHealthCheckResult(log, err)
+	return verified, err
+}
+
+// checkAdditionalObjectStores runs the object storage health check against each of the additionalStores. An
+// unavailable store does not block the deployment, pipeline runs only need it for the pipeline roots it serves.
+func (r *DSPAReconciler) checkAdditionalObjectStores(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) *dspav1.ObjectStorageStatus {
+
+	stores := params.ObjectStorageConnection.AdditionalStores
+	if len(stores) == 0 {
+		return nil
+	}
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	healthCheckDisabled := params.ObjectStorageHealthCheckDisabled(dsp)
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+
+	status := &dspav1.ObjectStorageStatus{}
+	for _, store := range stores {
+		storeStatus := dspav1.AdditionalObjectStoreStatus{Name: store.Name, Available: true}
+		if healthCheckDisabled {
+			status.AdditionalStores = append(status.AdditionalStores, storeStatus)
+			continue
+		}
+		storeLog := log.WithValues("additional_store", store.Name)
+		if err := isAdditionalObjectStoreAccessible(ctx, storeLog, store, params, objStoreConnectionTimeout); err != nil {
+			storeLog.Info(fmt.Sprintf("Additional object storage health check failed: %s", err.Error()))
+			storeStatus.Available = false
+			storeStatus.Message = err.Error()
+		}
+		status.AdditionalStores = append(status.AdditionalStores, storeStatus)
+	}
+	return status
+}
+
+func isAdditionalObjectStoreAccessible(ctx context.Context, log logr.Logger, store AdditionalObjectStoreConnection,
+	params *DSPAParams, objStoreConnectionTimeout time.Duration) error {
+
+	if store.AccessKeyID == "" || store.SecretAccessKey == "" {
+		return fmt.Errorf("object storage credentials from secret [%s] for keys [%s, %s] were not successfully retrieved, "+
+			"ensure that the secret with these keys exist", store.CredentialsSecret.SecretName,
+			store.CredentialsSecret.AccessKey, store.CredentialsSecret.SecretKey)
+	}
+	endpoint, err := joinHostPort(store.Host, store.Port)
+	if err != nil {
+		return errors.New("could not determine object storage endpoint")
+	}
+	accesskey, err := base64.StdEncoding.DecodeString(store.AccessKeyID)
+	if err != nil {
+		return errors.New("could not decode object storage access key ID")
+	}
+	secretkey, err := base64.StdEncoding.DecodeString(store.SecretAccessKey)
+	if err != nil {
+		return errors.New("could not decode object storage secret access key")
+	}
+
+	_, err = ConnectAndQueryObjStore(ctx, log, endpoint, store.Bucket, createCredentialProvidersChain(string(accesskey), string(secretkey)),
+		store.Secure, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+	return err
+}
+
+// getObjStoreCredentials returns the credentials of the s3 object storage connection, either the static keys or
+// web identity credentials.
+func (r *DSPAReconciler) getObjStoreCredentials(ctx context.Context, log logr.Logger, params *DSPAParams) (*credentials.Credentials, error) {
+	if params.ObjectStorageConnection.WebIdentity != nil {
+		cred, err := r.createWebIdentityCredentials(ctx, log, params)
+		if err != nil {
+			errorMessage := "could not create object storage web identity credentials"
+			log.Error(err, errorMessage)
+			return nil, errors.New(errorMessage)
+		}
+		return cred, nil
+	}
+
 	accesskey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.AccessKeyID)
 	if err != nil {
 		errorMessage := "could not decode object storage access key ID"
 		log.Error(err, errorMessage)
-		return false, errors.New(errorMessage)
+		return nil, errors.New(errorMessage)
 	}
 
 	secretkey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.SecretAccessKey)
 	if err != nil {
 		errorMessage := "could not decode object storage secret access key"
 		log.Error(err, errorMessage)
-		return false, errors.New(errorMessage)
+		return nil, errors.New(errorMessage)
 	}
+	return createCredentialProvidersChain(string(accesskey), string(secretkey)), nil
+}
 
-	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+func (r *DSPAReconciler) isGCSBucketAccessible(ctx context.Context, log logr.Logger, params *DSPAParams,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
 
-	log.V(1).Info(fmt.Sprintf("Object Store connection timeout: %s", objStoreConnectionTimeout))
+	serviceAccountKey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.GCSServiceAccountKey)
+	if err != nil {
+		errorMessage := "could not decode object storage service account key"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+	return ConnectAndQueryGCSBucket(ctx, log, params.ObjectStorageConnection.Endpoint, params.ObjectStorageConnection.Bucket,
+		serviceAccountKey, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+}
 
-	verified, err := ConnectAndQueryObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, accesskey, secretkey,
-		*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+func (r *DSPAReconciler) isAzureContainerAccessible(ctx context.Context, log logr.Logger, params *DSPAParams,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
 
+	accountKey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.AzureAccountKey)
+	if err != nil {
+		errorMessage := "could not decode object storage account key"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+	return ConnectAndQueryAzureContainer(ctx, log, params.ObjectStorageConnection.Endpoint, params.ObjectStorageConnection.Bucket,
+		params.ObjectStorageConnection.AzureAccountName, accountKey, params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout)
+}
+
+func logObjectStorageHealthCheckResult(log logr.Logger, err error) {
 	if err != nil {
 		log.Info("Object Storage Health Check Failed")
 	} else {
 		log.Info("Object Storage Health Check Successful")
 	}
-	return verified, err
 }
 
 // ReconcileStorage will set up Storage Connection.
@@ -282,6 +614,13 @@ func (r *DSPAReconciler) ReconcileStorage(ctx context.Context, dsp *dspav1.DataS
 	// If external storage is specified, it takes precedence
 	if externalStorageSpecified {
 		log.Info("Using externalStorage, bypassing object storage deployment.")
+		if params.UsingWebIdentityStorageCredentials(dsp) {
+			// The health check requests tokens for the API Server service account, before the API Server is deployed
+			err := r.Apply(dsp, params, apiServerServiceAccountTemplate)
+			if err != nil {
+				return err
+			}
+		}
 	} else if deployMinio {
 		log.Info("No S3 storage credential reference provided, so using managed secret")
 		if !storageCredentialsProvided {
@@ -292,13 +631,26 @@ func (r *DSPAReconciler) ReconcileStorage(ctx context.Context, dsp *dspav1.DataS
 		}
 		log.Info("Applying object storage resources.")
 		for _, template := range minioTemplates {
-			if dsp.Spec.ObjectStorage.EnableExternalRoute || template != storageRoute {
-				err := r.Apply(dsp, params, template)
-				if err != nil {
-					return err
-				}
+			err := r.Apply(dsp, params, template)
+			if err != nil {
+				return err
 			}
 		}
+		var minioExposure *dspav1.Exposure
+		if dsp.Spec.ObjectStorage.Minio != nil {
+			minioExposure = dsp.Spec.ObjectStorage.Minio.Exposure
+		}
+		var err error
+		params.MinioExposure, err = r.resolveExposure(minioExposure, dsp.Spec.ObjectStorage.EnableExternalRoute)
+		if err != nil {
+			return err
+		}
+		exposureTemplates := minioExposureTemplates
+		exposureTemplates.name = config.MinioHostPrefix + "-" + dsp.Name
+		err = r.reconcileExposure(ctx, dsp, params, &params.MinioExposure, exposureTemplates)
+		if err != nil {
+			return err
+		}
 		// If no storage was not specified, deploy minio by default.
 		// Update the CR with the state of minio to accurately portray
 		// desired state.
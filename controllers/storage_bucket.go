@@ -0,0 +1,436 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+)
+
+// managedBucket is the desired state of an operator managed bucket
+type managedBucket struct {
+	Bucket          string
+	Region          string
+	CreateIfMissing bool
+	Versioning      string
+	// LifecycleRuleID identifies the artifact expiration rule owned by the DSPA, other rules are left untouched
+	LifecycleRuleID string
+	// LegacyLifecycleRuleID is the rule ID used before it included the namespace. A rule with this ID on the same
+	// prefix is replaced by the LifecycleRuleID rule
+	LegacyLifecycleRuleID string
+	// LifecyclePrefix limits the artifact expiration rule to the base path of the DSPA
+	LifecyclePrefix string
+	// ExpirationDays of 0 removes the artifact expiration rule
+	ExpirationDays int
+}
+
+// ConnectAndManageObjStoreBucket creates the bucket when allowed and brings its versioning and artifact expiration
+// lifecycle rule in line with the desired state. It returns the drift it corrected. Drift that could not be corrected
+// is returned as ObjectStoreHealthCheckError with the BucketConfigurationDrift reason.
+var ConnectAndManageObjStoreBucket = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint string,
+	cred *credentials.Credentials,
+	secure bool,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration,
+	desired managedBucket) ([]string, error) {
+
+	minioClient, err := newObjStoreClient(log, endpoint, cred, secure, pemCerts, proxyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	var corrected []string
+
+	exists, err := minioClient.BucketExists(ctx, desired.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("could not verify that bucket %s exists: %w", desired.Bucket, err)
+	}
+	if !exists {
+		if !desired.CreateIfMissing {
+			return nil, &ObjectStoreHealthCheckError{
+				Reason: config.BucketNotFound,
+				Err:    fmt.Errorf("bucket %s does not exist, set manageBucket.createIfMissing to create it", desired.Bucket),
+			}
+		}
+		if err := minioClient.MakeBucket(ctx, desired.Bucket, minio.MakeBucketOptions{Region: desired.Region}); err != nil {
+			return nil, fmt.Errorf("could not create bucket %s: %w", desired.Bucket, err)
+		}
+		log.Info(fmt.Sprintf("Created bucket %s", desired.Bucket))
+		corrected = append(corrected, fmt.Sprintf("bucket %s was created", desired.Bucket))
+	}
+
+	if desired.Versioning != "" {
+		versioning, err := minioClient.GetBucketVersioning(ctx, desired.Bucket)
+		if err != nil {
+			return corrected, fmt.Errorf("could not get the versioning of bucket %s: %w", desired.Bucket, err)
+		}
+		if versioning.Status != desired.Versioning {
+			drift := fmt.Sprintf("versioning was %s instead of %s", bucketVersioningStatus(versioning.Status), desired.Versioning)
+			if desired.Versioning == minio.Enabled {
+				err = minioClient.EnableVersioning(ctx, desired.Bucket)
+			} else {
+				err = minioClient.SuspendVersioning(ctx, desired.Bucket)
+			}
+			if err != nil {
+				return corrected, bucketDriftError(drift, err)
+			}
+			corrected = append(corrected, drift)
+		}
+	}
+
+	lifecycleConfig, err := minioClient.GetBucketLifecycle(ctx, desired.Bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+			return corrected, fmt.Errorf("could not get the lifecycle configuration of bucket %s: %w", desired.Bucket, err)
+		}
+		lifecycleConfig = lifecycle.NewConfiguration()
+	}
+	drift, updatedRules := reconcileArtifactExpirationRule(lifecycleConfig.Rules, desired)
+	if drift != "" {
+		lifecycleConfig.Rules = updatedRules
+		if err := minioClient.SetBucketLifecycle(ctx, desired.Bucket, lifecycleConfig); err != nil {
+			return corrected, bucketDriftError(drift, err)
+		}
+		corrected = append(corrected, drift)
+	}
+
+	return corrected, nil
+}
+
+// reconcileArtifactExpirationRule returns the lifecycle rules with the artifact expiration rule of the DSPA set to the
+// desired state, and a description of the drift, which is empty when the rules are already in sync.
+func reconcileArtifactExpirationRule(rules []lifecycle.Rule, desired managedBucket) (string, []lifecycle.Rule) {
+	updatedRules := make([]lifecycle.Rule, 0, len(rules)+1)
+	var actualRule, legacyRule *lifecycle.Rule
+	for i := range rules {
+		if rules[i].ID == desired.LifecycleRuleID {
+			actualRule = &rules[i]
+		} else if desired.LegacyLifecycleRuleID != "" && rules[i].ID == desired.LegacyLifecycleRuleID &&
+			rules[i].RuleFilter.Prefix+rules[i].Prefix == desired.LifecyclePrefix {
+			legacyRule = &rules[i]
+		} else {
+			updatedRules = append(updatedRules, rules[i])
+		}
+	}
+
+	if desired.ExpirationDays == 0 {
+		switch {
+		case actualRule != nil:
+			return fmt.Sprintf("lifecycle rule %s was no longer desired", desired.LifecycleRuleID), updatedRules
+		case legacyRule != nil:
+			return fmt.Sprintf("lifecycle rule %s was no longer desired", desired.LegacyLifecycleRuleID), updatedRules
+		}
+		return "", rules
+	}
+
+	desiredRule := lifecycle.Rule{
+		ID:         desired.LifecycleRuleID,
+		Status:     "Enabled",
+		RuleFilter: lifecycle.Filter{Prefix: desired.LifecyclePrefix},
+		Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(desired.ExpirationDays)},
+	}
+	if desired.Versioning == minio.Enabled {
+		desiredRule.NoncurrentVersionExpiration = lifecycle.NoncurrentVersionExpiration{
+			NoncurrentDays: lifecycle.ExpirationDays(desired.ExpirationDays),
+		}
+	}
+	updatedRules = append(updatedRules, desiredRule)
+
+	switch {
+	case legacyRule != nil:
+		return fmt.Sprintf("lifecycle rule %s was replaced by %s", desired.LegacyLifecycleRuleID, desired.LifecycleRuleID),
+			updatedRules
+	case actualRule == nil:
+		return fmt.Sprintf("lifecycle rule %s was missing", desired.LifecycleRuleID), updatedRules
+	case actualRule.Status != desiredRule.Status:
+		return fmt.Sprintf("lifecycle rule %s was %s", desired.LifecycleRuleID, actualRule.Status), updatedRules
+	case actualRule.RuleFilter.Prefix != desiredRule.RuleFilter.Prefix || actualRule.Prefix != "":
+		return fmt.Sprintf("lifecycle rule %s applied to prefix %q instead of %q", desired.LifecycleRuleID,
+			actualRule.RuleFilter.Prefix+actualRule.Prefix, desiredRule.RuleFilter.Prefix), updatedRules
+	case actualRule.Expiration.Days != desiredRule.Expiration.Days ||
+		actualRule.NoncurrentVersionExpiration.NoncurrentDays != desiredRule.NoncurrentVersionExpiration.NoncurrentDays:
+		return fmt.Sprintf("lifecycle rule %s expired artifacts after %d days instead of %d", desired.LifecycleRuleID,
+			actualRule.Expiration.Days, desiredRule.Expiration.Days), updatedRules
+	}
+	return "", rules
+}
+
+// managedBucketLifecycleRuleID returns the ID of the artifact expiration lifecycle rule of a DSPA. Rules of same-named
+// DSPAs in different namespaces sharing a bucket must not collide, and long IDs are truncated with a hash suffix to
+// stay within the rule ID length limit.
+func managedBucketLifecycleRuleID(namespace, name string) string {
+	ruleID := config.ManagedBucketLifecycleRulePrefix + namespace + "." + name
+	if len(ruleID) <= config.ManagedBucketLifecycleRuleIDMaxLength {
+		return ruleID
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(ruleID)))[:16]
+	return ruleID[:config.ManagedBucketLifecycleRuleIDMaxLength-len(hash)-1] + "-" + hash
+}
+
+func bucketVersioningStatus(status string) string {
+	if status == "" {
+		return "disabled"
+	}
+	return status
+}
+
+func bucketDriftError(drift string, err error) error {
+	return &ObjectStoreHealthCheckError{
+		Reason: config.BucketConfigurationDrift,
+		Err:    fmt.Errorf("%s and could not be corrected: %w", drift, err),
+	}
+}
+
+// validateArtifactExpiration refuses artifact expiration rules that would also expire objects the DSPA does not treat
+// as artifacts, such as the pipelines stored at the root of the bucket or the MariaDB backups.
+func validateArtifactExpiration(dsp *dspav1.DataSciencePipelinesApplication, params *DSPAParams, desired managedBucket) error {
+	if desired.ExpirationDays == 0 {
+		return nil
+	}
+	if desired.LifecyclePrefix == "" {
+		return &ObjectStoreHealthCheckError{
+			Reason: config.InvalidArtifactExpiration,
+			Err:    errors.New("manageBucket.artifactExpirationDays requires a basePath, the lifecycle rule would otherwise expire every object in the bucket"),
+		}
+	}
+	if params.UsingMariaDBBackup(dsp) && strings.HasPrefix(params.MariaDB.Backup.ObjectStoragePrefix+"/", desired.LifecyclePrefix) {
+		return &ObjectStoreHealthCheckError{
+			Reason: config.InvalidArtifactExpiration,
+			Err: fmt.Errorf("manageBucket.artifactExpirationDays would expire the MariaDB backups under %s, move them out of the basePath",
+				params.MariaDB.Backup.ObjectStoragePrefix),
+		}
+	}
+	return nil
+}
+
+// ReconcileManagedBucket applies the manageBucket configuration of the external s3 storage. It returns false when
+// the bucket is not managed by the operator, and the drift it corrected otherwise.
+func (r *DSPAReconciler) ReconcileManagedBucket(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, []string, error) {
+
+	manageBucket := params.ObjectStorageConnection.ManageBucket
+	if manageBucket == nil {
+		return false, nil, nil
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	log.Info("Reconciling managed object storage bucket")
+
+	endpoint, err := joinHostPort(params.ObjectStorageConnection.Host, params.ObjectStorageConnection.Port)
+	if err != nil {
+		errorMessage := "could not determine object storage endpoint"
+		log.Error(err, errorMessage)
+		return true, nil, errors.New(errorMessage)
+	}
+
+	cred, err := r.getObjStoreCredentials(ctx, log, params)
+	if err != nil {
+		return true, nil, err
+	}
+
+	desired := managedBucket{
+		Bucket:                params.ObjectStorageConnection.Bucket,
+		CreateIfMissing:       manageBucket.CreateIfMissing,
+		Versioning:            manageBucket.Versioning,
+		LifecycleRuleID:       managedBucketLifecycleRuleID(dsp.Namespace, dsp.Name),
+		LegacyLifecycleRuleID: config.ManagedBucketLifecycleRulePrefix + dsp.Name,
+	}
+	if region := params.ObjectStorageConnection.Region; region != "auto" {
+		desired.Region = region
+	}
+	if basePath := strings.Trim(params.ObjectStorageConnection.BasePath, "/"); basePath != "" {
+		desired.LifecyclePrefix = basePath + "/"
+	}
+	if manageBucket.ArtifactExpirationDays != nil {
+		desired.ExpirationDays = int(*manageBucket.ArtifactExpirationDays)
+	}
+	if err := validateArtifactExpiration(dsp, params, desired); err != nil {
+		log.Info(fmt.Sprintf("Refusing to manage bucket %s: %s", desired.Bucket, err.Error()))
+		return true, nil, err
+	}
+
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+
+	corrected, err := ConnectAndManageObjStoreBucket(ctx, log, endpoint, cred, *params.ObjectStorageConnection.Secure,
+		params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout, desired)
+	if err != nil {
+		log.Info(fmt.Sprintf("Could not reconcile managed bucket %s: %s", desired.Bucket, err.Error()))
+		return true, corrected, err
+	}
+	for _, drift := range corrected {
+		log.Info(fmt.Sprintf("Corrected managed bucket %s: %s", desired.Bucket, drift))
+	}
+	return true, corrected, nil
+}
+
+// ConnectAndReconcileObjStoreBucketEncryption compares the default encryption of the bucket with the server-side
+// encryption of the DSPA, which the KFP API server and launcher rely on since they do not request it themselves. A
+// mismatch is corrected when enforce is set and returned as ObjectStoreHealthCheckError with the
+// BucketEncryptionMismatch reason otherwise. It returns the drift it corrected.
+var ConnectAndReconcileObjStoreBucketEncryption = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint string,
+	cred *credentials.Credentials,
+	secure bool,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration,
+	bucket string,
+	desired *dspav1.ObjectStorageEncryption,
+	enforce bool) (string, error) {
+
+	minioClient, err := newObjStoreClient(log, endpoint, cred, secure, pemCerts, proxyConfig)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	actual, err := minioClient.GetBucketEncryption(ctx, bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "ServerSideEncryptionConfigurationNotFoundError" {
+			return "", fmt.Errorf("could not get the default encryption of bucket %s: %w", bucket, err)
+		}
+		actual = nil
+	}
+	if bucketEncryptionMatches(actual, desired) {
+		return "", nil
+	}
+
+	drift := fmt.Sprintf("default encryption of bucket %s was %s instead of %s", bucket,
+		describeBucketEncryption(actual), describeObjectStorageEncryption(desired))
+	if !enforce {
+		return "", &ObjectStoreHealthCheckError{
+			Reason: config.BucketEncryptionMismatch,
+			Err: fmt.Errorf("%s, the pipeline artifacts written by the API server and launcher are not encrypted as "+
+				"configured; set manageBucket to let the operator correct it", drift),
+		}
+	}
+
+	defaultEncryption := sse.NewConfigurationSSES3()
+	if desired.Type == config.ObjectStorageEncryptionSSEKMS {
+		defaultEncryption = sse.NewConfigurationSSEKMS(desired.KMSKeyID)
+	}
+	if err := minioClient.SetBucketEncryption(ctx, bucket, defaultEncryption); err != nil {
+		return "", &ObjectStoreHealthCheckError{
+			Reason: config.BucketEncryptionMismatch,
+			Err:    fmt.Errorf("%s and could not be corrected: %w", drift, err),
+		}
+	}
+	return drift, nil
+}
+
+// bucketEncryptionMatches returns whether the default encryption of a bucket encrypts with the desired server-side
+// encryption. KMS keys match by ID or ARN.
+func bucketEncryptionMatches(actual *sse.Configuration, desired *dspav1.ObjectStorageEncryption) bool {
+	if actual == nil || len(actual.Rules) == 0 {
+		return false
+	}
+	apply := actual.Rules[0].Apply
+	if desired.Type != config.ObjectStorageEncryptionSSEKMS {
+		return apply.SSEAlgorithm == "AES256"
+	}
+	return apply.SSEAlgorithm == "aws:kms" && (apply.KmsMasterKeyID == desired.KMSKeyID ||
+		strings.HasSuffix(apply.KmsMasterKeyID, "/"+desired.KMSKeyID) ||
+		strings.HasSuffix(desired.KMSKeyID, "/"+apply.KmsMasterKeyID))
+}
+
+func describeBucketEncryption(actual *sse.Configuration) string {
+	if actual == nil || len(actual.Rules) == 0 {
+		return "disabled"
+	}
+	apply := actual.Rules[0].Apply
+	switch apply.SSEAlgorithm {
+	case "AES256":
+		return config.ObjectStorageEncryptionSSES3
+	case "aws:kms":
+		if apply.KmsMasterKeyID == "" {
+			return config.ObjectStorageEncryptionSSEKMS + " with the default key"
+		}
+		return fmt.Sprintf("%s with key %s", config.ObjectStorageEncryptionSSEKMS, apply.KmsMasterKeyID)
+	}
+	return apply.SSEAlgorithm
+}
+
+func describeObjectStorageEncryption(desired *dspav1.ObjectStorageEncryption) string {
+	if desired.Type == config.ObjectStorageEncryptionSSEKMS {
+		return fmt.Sprintf("%s with key %s", config.ObjectStorageEncryptionSSEKMS, desired.KMSKeyID)
+	}
+	return config.ObjectStorageEncryptionSSES3
+}
+
+// ReconcileBucketEncryption verifies the default encryption of the bucket against the server-side encryption of the
+// DSPA, and sets it when the bucket is managed by the operator. It returns false when no server-side encryption is
+// configured, and the drift it corrected otherwise.
+func (r *DSPAReconciler) ReconcileBucketEncryption(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, string, error) {
+
+	encryption := params.ObjectStorageConnection.Encryption
+	if encryption == nil {
+		return false, "", nil
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	log.Info("Reconciling object storage bucket default encryption")
+
+	endpoint, err := joinHostPort(params.ObjectStorageConnection.Host, params.ObjectStorageConnection.Port)
+	if err != nil {
+		errorMessage := "could not determine object storage endpoint"
+		log.Error(err, errorMessage)
+		return true, "", errors.New(errorMessage)
+	}
+
+	cred, err := r.getObjStoreCredentials(ctx, log, params)
+	if err != nil {
+		return true, "", err
+	}
+
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+
+	bucket := params.ObjectStorageConnection.Bucket
+	corrected, err := ConnectAndReconcileObjStoreBucketEncryption(ctx, log, endpoint, cred, *params.ObjectStorageConnection.Secure,
+		params.APICustomPemCerts, params.ProxyConfig, objStoreConnectionTimeout, bucket, encryption,
+		params.ObjectStorageConnection.ManageBucket != nil)
+	if err != nil {
+		log.Info(fmt.Sprintf("Could not reconcile the default encryption of bucket %s: %s", bucket, err.Error()))
+		return true, "", err
+	}
+	if corrected != "" {
+		log.Info(fmt.Sprintf("Corrected bucket %s: %s", bucket, corrected))
+	}
+	return true, corrected, nil
+}
@@ -0,0 +1,379 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Bucket serves the bucket APIs used by bucket management for a single bucket
+type fakeS3Bucket struct {
+	mu                sync.Mutex
+	exists            bool
+	versioning        string
+	lifecycle         string
+	denyLifecyclePuts bool
+	encryption        string
+}
+
+func (b *fakeS3Bucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	writeError := func(status int, code string) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+		}
+	}
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+
+	_, location := query["location"]
+	_, versioning := query["versioning"]
+	_, lifecycleQuery := query["lifecycle"]
+	_, encryption := query["encryption"]
+	switch {
+	case location:
+		_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+	case r.Method == http.MethodPut && !versioning && !lifecycleQuery && !encryption:
+		b.exists = true
+	case !b.exists:
+		writeError(http.StatusNotFound, "NoSuchBucket")
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case versioning && r.Method == http.MethodGet:
+		_, _ = fmt.Fprintf(w, "<VersioningConfiguration><Status>%s</Status></VersioningConfiguration>", b.versioning)
+	case versioning && r.Method == http.MethodPut:
+		status := string(body)
+		b.versioning = status[strings.Index(status, "<Status>")+len("<Status>") : strings.Index(status, "</Status>")]
+	case lifecycleQuery && r.Method == http.MethodGet:
+		if b.lifecycle == "" {
+			writeError(http.StatusNotFound, "NoSuchLifecycleConfiguration")
+			return
+		}
+		_, _ = w.Write([]byte(b.lifecycle))
+	case lifecycleQuery && r.Method == http.MethodPut:
+		if b.denyLifecyclePuts {
+			writeError(http.StatusForbidden, "AccessDenied")
+			return
+		}
+		b.lifecycle = string(body)
+	case lifecycleQuery && r.Method == http.MethodDelete:
+		b.lifecycle = ""
+		w.WriteHeader(http.StatusNoContent)
+	case encryption && r.Method == http.MethodGet:
+		if b.encryption == "" {
+			writeError(http.StatusNotFound, "ServerSideEncryptionConfigurationNotFoundError")
+			return
+		}
+		_, _ = w.Write([]byte(b.encryption))
+	case encryption && r.Method == http.MethodPut:
+		b.encryption = string(body)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestConnectAndManageObjStoreBucket(t *testing.T) {
+	bucket := &fakeS3Bucket{}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	desired := managedBucket{
+		Bucket:          "mlpipeline",
+		CreateIfMissing: true,
+		Versioning:      "Enabled",
+		LifecycleRuleID: "dspa-artifact-expiration-testdspa",
+		LifecyclePrefix: "some/path/",
+		ExpirationDays:  30,
+	}
+	manage := func(desired managedBucket) ([]string, error) {
+		return ConnectAndManageObjStoreBucket(t.Context(), logr.Discard(), strings.TrimPrefix(server.URL, "http://"),
+			credentials.NewStaticV4("fooaccesskey", "foosecretkey", ""), false, nil, nil, 10*time.Second, desired)
+	}
+
+	corrected, err := manage(desired)
+	require.NoError(t, err)
+	assert.Len(t, corrected, 3)
+	assert.True(t, bucket.exists)
+	assert.Equal(t, "Enabled", bucket.versioning)
+	assert.Contains(t, bucket.lifecycle, "<ID>dspa-artifact-expiration-testdspa</ID>")
+	assert.Contains(t, bucket.lifecycle, "<Prefix>some/path/</Prefix>")
+	assert.Contains(t, bucket.lifecycle, "<Days>30</Days>")
+
+	corrected, err = manage(desired)
+	require.NoError(t, err)
+	assert.Empty(t, corrected)
+
+	// Drift introduced outside of the operator is corrected
+	bucket.lifecycle = strings.Replace(bucket.lifecycle, "<Days>30</Days>", "<Days>1</Days>", 1)
+	corrected, err = manage(desired)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lifecycle rule dspa-artifact-expiration-testdspa expired artifacts after 1 days instead of 30"}, corrected)
+
+	bucket.lifecycle = ""
+	bucket.denyLifecyclePuts = true
+	_, err = manage(desired)
+	assert.Equal(t, config.BucketConfigurationDrift, objectStoreHealthCheckReason(err))
+
+	bucket.exists = false
+	desired.CreateIfMissing = false
+	_, err = manage(desired)
+	assert.Equal(t, config.BucketNotFound, objectStoreHealthCheckReason(err))
+}
+
+func TestConnectAndReconcileObjStoreBucketEncryption(t *testing.T) {
+	bucket := &fakeS3Bucket{exists: true}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	desired := &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSEKMS, KMSKeyID: "dspa"}
+	reconcileEncryption := func(enforce bool) (string, error) {
+		return ConnectAndReconcileObjStoreBucketEncryption(t.Context(), logr.Discard(), strings.TrimPrefix(server.URL, "http://"),
+			credentials.NewStaticV4("fooaccesskey", "foosecretkey", ""), false, nil, nil, 10*time.Second, "mlpipeline",
+			desired, enforce)
+	}
+
+	// A mismatch is only reported when the bucket is not managed by the operator
+	_, err := reconcileEncryption(false)
+	assert.Equal(t, config.BucketEncryptionMismatch, objectStoreHealthCheckReason(err))
+	assert.Contains(t, err.Error(), "was disabled instead of SSE-KMS with key dspa")
+	assert.Empty(t, bucket.encryption)
+
+	corrected, err := reconcileEncryption(true)
+	require.NoError(t, err)
+	assert.Equal(t, "default encryption of bucket mlpipeline was disabled instead of SSE-KMS with key dspa", corrected)
+	assert.Contains(t, bucket.encryption, "<SSEAlgorithm>aws:kms</SSEAlgorithm>")
+	assert.Contains(t, bucket.encryption, "<KMSMasterKeyID>dspa</KMSMasterKeyID>")
+
+	corrected, err = reconcileEncryption(false)
+	require.NoError(t, err)
+	assert.Empty(t, corrected)
+
+	// KMS keys match by ID or ARN
+	bucket.encryption = strings.Replace(bucket.encryption, "<KMSMasterKeyID>dspa</KMSMasterKeyID>",
+		"<KMSMasterKeyID>arn:aws:kms:us-east-1:123456789012:key/dspa</KMSMasterKeyID>", 1)
+	_, err = reconcileEncryption(false)
+	require.NoError(t, err)
+
+	desired = &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSES3}
+	_, err = reconcileEncryption(false)
+	assert.Equal(t, config.BucketEncryptionMismatch, objectStoreHealthCheckReason(err))
+	assert.Contains(t, err.Error(), "was SSE-KMS with key arn:aws:kms:us-east-1:123456789012:key/dspa instead of SSE-S3")
+}
+
+func TestReconcileBucketEncryption(t *testing.T) {
+	var enforced *bool
+	ConnectAndReconcileObjStoreBucketEncryption = func(ctx context.Context, log logr.Logger, endpoint string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration, bucket string, desired *dspav1.ObjectStorageEncryption, enforce bool) (string, error) {
+		enforced = &enforce
+		return "", nil
+	}
+
+	dspa := &dspav1.DataSciencePipelinesApplication{}
+	dspa.Name = "testdspa"
+	dspa.Namespace = "testnamespace"
+	ctx, _, reconciler := CreateNewTestObjects()
+
+	secureConnection := false
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "foo",
+			Port:            "1337",
+			Bucket:          "mlpipeline",
+			Secure:          &secureConnection,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+
+	encrypted, _, err := reconciler.ReconcileBucketEncryption(ctx, dspa, params)
+	assert.False(t, encrypted)
+	assert.NoError(t, err)
+	assert.Nil(t, enforced)
+
+	params.ObjectStorageConnection.Encryption = &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSES3}
+	encrypted, _, err = reconciler.ReconcileBucketEncryption(ctx, dspa, params)
+	assert.True(t, encrypted)
+	assert.NoError(t, err)
+	require.NotNil(t, enforced)
+	assert.False(t, *enforced)
+
+	// The default encryption of managed buckets is set by the operator
+	params.ObjectStorageConnection.ManageBucket = &dspav1.ManageBucket{}
+	_, _, err = reconciler.ReconcileBucketEncryption(ctx, dspa, params)
+	assert.NoError(t, err)
+	assert.True(t, *enforced)
+}
+
+func TestReconcileArtifactExpirationRule(t *testing.T) {
+	desired := managedBucket{LifecycleRuleID: "dspa-artifact-expiration-testdspa", LifecyclePrefix: "dspa/", ExpirationDays: 7}
+	desiredRule := lifecycle.Rule{
+		ID:         "dspa-artifact-expiration-testdspa",
+		Status:     "Enabled",
+		RuleFilter: lifecycle.Filter{Prefix: "dspa/"},
+		Expiration: lifecycle.Expiration{Days: 7},
+	}
+	foreignRule := lifecycle.Rule{ID: "other", Status: "Enabled", Expiration: lifecycle.Expiration{Days: 1}}
+
+	drift, rules := reconcileArtifactExpirationRule([]lifecycle.Rule{foreignRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-testdspa was missing", drift)
+	assert.Equal(t, []lifecycle.Rule{foreignRule, desiredRule}, rules)
+
+	drift, rules = reconcileArtifactExpirationRule([]lifecycle.Rule{desiredRule, foreignRule}, desired)
+	assert.Empty(t, drift)
+	assert.Equal(t, []lifecycle.Rule{desiredRule, foreignRule}, rules)
+
+	disabledRule := desiredRule
+	disabledRule.Status = "Disabled"
+	drift, _ = reconcileArtifactExpirationRule([]lifecycle.Rule{disabledRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-testdspa was Disabled", drift)
+
+	movedRule := desiredRule
+	movedRule.RuleFilter.Prefix = ""
+	drift, _ = reconcileArtifactExpirationRule([]lifecycle.Rule{movedRule}, desired)
+	assert.Equal(t, `lifecycle rule dspa-artifact-expiration-testdspa applied to prefix "" instead of "dspa/"`, drift)
+
+	// The rule of a same-named DSPA in another namespace is left untouched, its own rule replaces the legacy rule
+	desired.LegacyLifecycleRuleID = "dspa-artifact-expiration-legacy"
+	legacyRule := desiredRule
+	legacyRule.ID = "dspa-artifact-expiration-legacy"
+	otherNamespaceRule := legacyRule
+	otherNamespaceRule.RuleFilter.Prefix = "other/"
+	drift, rules = reconcileArtifactExpirationRule([]lifecycle.Rule{otherNamespaceRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-testdspa was missing", drift)
+	assert.Equal(t, []lifecycle.Rule{otherNamespaceRule, desiredRule}, rules)
+
+	drift, rules = reconcileArtifactExpirationRule([]lifecycle.Rule{legacyRule, foreignRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-legacy was replaced by dspa-artifact-expiration-testdspa", drift)
+	assert.Equal(t, []lifecycle.Rule{foreignRule, desiredRule}, rules)
+
+	desired.ExpirationDays = 0
+	drift, rules = reconcileArtifactExpirationRule([]lifecycle.Rule{desiredRule, foreignRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-testdspa was no longer desired", drift)
+	assert.Equal(t, []lifecycle.Rule{foreignRule}, rules)
+
+	drift, rules = reconcileArtifactExpirationRule([]lifecycle.Rule{legacyRule, foreignRule}, desired)
+	assert.Equal(t, "lifecycle rule dspa-artifact-expiration-legacy was no longer desired", drift)
+	assert.Equal(t, []lifecycle.Rule{foreignRule}, rules)
+}
+
+func TestManagedBucketLifecycleRuleID(t *testing.T) {
+	assert.Equal(t, "dspa-artifact-expiration-testnamespace.testdspa", managedBucketLifecycleRuleID("testnamespace", "testdspa"))
+	assert.NotEqual(t, managedBucketLifecycleRuleID("test-namespace", "dspa"), managedBucketLifecycleRuleID("test", "namespace-dspa"))
+
+	longName := strings.Repeat("a", 253)
+	ruleID := managedBucketLifecycleRuleID("testnamespace", longName)
+	assert.Len(t, ruleID, config.ManagedBucketLifecycleRuleIDMaxLength)
+	assert.True(t, strings.HasPrefix(ruleID, "dspa-artifact-expiration-testnamespace.aaa"))
+	assert.NotEqual(t, ruleID, managedBucketLifecycleRuleID("othernamespace", longName))
+}
+
+func TestReconcileManagedBucket(t *testing.T) {
+	var desiredBucket managedBucket
+	ConnectAndManageObjStoreBucket = func(ctx context.Context, log logr.Logger, endpoint string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration, desired managedBucket) ([]string, error) {
+		desiredBucket = desired
+		return []string{"lifecycle rule dspa-artifact-expiration-testdspa was missing"}, nil
+	}
+
+	dspa := &dspav1.DataSciencePipelinesApplication{}
+	dspa.Name = "testdspa"
+	dspa.Namespace = "testnamespace"
+	ctx, _, reconciler := CreateNewTestObjects()
+
+	SecureConnection := false
+	expirationDays := int32(14)
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "foo",
+			Port:            "1337",
+			Bucket:          "mlpipeline",
+			BasePath:        "/some/path/",
+			Region:          "auto",
+			Secure:          &SecureConnection,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+
+	managed, _, err := reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.False(t, managed)
+	assert.NoError(t, err)
+
+	params.ObjectStorageConnection.ManageBucket = &dspav1.ManageBucket{
+		CreateIfMissing:        true,
+		ArtifactExpirationDays: &expirationDays,
+		Versioning:             "Suspended",
+	}
+	managed, corrected, err := reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.True(t, managed)
+	assert.NoError(t, err)
+	assert.Len(t, corrected, 1)
+	assert.Equal(t, managedBucket{
+		Bucket:                "mlpipeline",
+		CreateIfMissing:       true,
+		Versioning:            "Suspended",
+		LifecycleRuleID:       "dspa-artifact-expiration-testnamespace.testdspa",
+		LegacyLifecycleRuleID: "dspa-artifact-expiration-testdspa",
+		LifecyclePrefix:       "some/path/",
+		ExpirationDays:        14,
+	}, desiredBucket)
+
+	// Artifact expiration would cover the whole bucket without a basePath
+	desiredBucket = managedBucket{}
+	params.ObjectStorageConnection.BasePath = "/"
+	_, _, err = reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.Equal(t, config.InvalidArtifactExpiration, objectStoreHealthCheckReason(err))
+	assert.Empty(t, desiredBucket.Bucket)
+
+	// Artifact expiration would cover the MariaDB backups
+	params.ObjectStorageConnection.BasePath = "backups"
+	params.MariaDB = &dspav1.MariaDB{Deploy: true, Backup: &dspav1.MariaDBBackup{ObjectStoragePrefix: "backups/mariadb"}}
+	_, _, err = reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.Equal(t, config.InvalidArtifactExpiration, objectStoreHealthCheckReason(err))
+	assert.Empty(t, desiredBucket.Bucket)
+
+	params.ObjectStorageConnection.BasePath = "backups-artifacts"
+	_, _, err = reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "backups-artifacts/", desiredBucket.LifecyclePrefix)
+
+	// Without artifact expiration the bucket is managed regardless of the basePath
+	params.ObjectStorageConnection.BasePath = ""
+	params.ObjectStorageConnection.ManageBucket.ArtifactExpirationDays = nil
+	_, _, err = reconciler.ReconcileManagedBucket(ctx, dspa, params)
+	assert.NoError(t, err)
+}
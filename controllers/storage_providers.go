@@ -0,0 +1,210 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	gcsDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	gcsReadOnlyScope   = "https://www.googleapis.com/auth/devstorage.read_only"
+	azureStorageAPI    = "2021-08-06"
+)
+
+// gcsServiceAccountKey holds the fields of a JSON service account key needed to request access tokens
+type gcsServiceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ConnectAndQueryGCSBucket lists at most one object of the bucket with an access token of the service account key,
+// verifying both the credentials and the storage.objects.list permission.
+var ConnectAndQueryGCSBucket = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint, bucket string,
+	serviceAccountKey []byte,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
+
+	key := gcsServiceAccountKey{}
+	if err := json.Unmarshal(serviceAccountKey, &key); err != nil {
+		errorMessage := "could not parse the GCS service account key, ensure the secret contains a JSON service account key"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = gcsDefaultTokenURL
+	}
+	jwtConfig := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{gcsReadOnlyScope},
+		TokenURL:     tokenURL,
+	}
+
+	transport, err := getTransportWithProxyAndCACert(log, pemCerts, proxyConfig, true)
+	if err != nil {
+		errorMessage := "encountered error when processing custom ca bundle or proxy configuration"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+
+	requestURL := fmt.Sprintf("%s/storage/v1/b/%s/o?maxResults=1&fields=kind", endpoint, url.PathEscape(bucket))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return false, err
+	}
+	response, err := jwtConfig.Client(ctx).Do(request)
+	return checkObjectStorageResponse(log, endpoint, response, err)
+}
+
+// ConnectAndQueryAzureContainer lists at most one blob of the container with a Shared Key signed request,
+// verifying both the storage account key and the container access.
+var ConnectAndQueryAzureContainer = func(
+	ctx context.Context,
+	log logr.Logger,
+	endpoint, container, accountName string,
+	accountKey []byte,
+	pemCerts [][]byte,
+	proxyConfig *dspav1.ProxyConfig,
+	objStoreConnectionTimeout time.Duration) (bool, error) {
+
+	transport, err := getTransportWithProxyAndCACert(log, pemCerts, proxyConfig, strings.HasPrefix(endpoint, "https"))
+	if err != nil {
+		errorMessage := "encountered error when processing custom ca bundle or proxy configuration"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	requestURL := fmt.Sprintf("%s/%s?restype=container&comp=list&maxresults=1", endpoint, url.PathEscape(container))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	request.Header.Set("x-ms-version", azureStorageAPI)
+	signature, err := signAzureSharedKey(request, accountName, accountKey)
+	if err != nil {
+		errorMessage := "could not sign the request with the Azure storage account key, ensure the secret contains a valid account key"
+		log.Error(err, errorMessage)
+		return false, errors.New(errorMessage)
+	}
+	request.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", accountName, signature))
+
+	response, err := (&http.Client{Transport: transport}).Do(request)
+	return checkObjectStorageResponse(log, endpoint, response, err)
+}
+
+// signAzureSharedKey returns the Shared Key signature of a request without a body, see
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func signAzureSharedKey(request *http.Request, accountName string, accountKey []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(string(accountKey))
+	if err != nil {
+		return "", err
+	}
+
+	var headers []string
+	for name := range request.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			headers = append(headers, name)
+		}
+	}
+	sort.Strings(headers)
+	var canonicalizedHeaders strings.Builder
+	for _, name := range headers {
+		canonicalizedHeaders.WriteString(name + ":" + request.Header.Get(name) + "\n")
+	}
+
+	canonicalizedResource := "/" + accountName + request.URL.EscapedPath()
+	query := request.URL.Query()
+	params := make([]string, 0, len(query))
+	for name := range query {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	for _, name := range params {
+		canonicalizedResource += "\n" + strings.ToLower(name) + ":" + strings.Join(query[name], ",")
+	}
+
+	// The standard headers are all empty, as the request has no body and no conditions
+	stringToSign := request.Method + strings.Repeat("\n", 12) + canonicalizedHeaders.String() + canonicalizedResource
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// checkObjectStorageResponse interprets the response of a provider health check request, mirroring the s3 health
+// check: a missing bucket still proves the endpoint and credentials work, any other failure does not.
+func checkObjectStorageResponse(log logr.Logger, endpoint string, response *http.Response, err error) (bool, error) {
+	if err != nil {
+		if util.IsX509UnknownAuthorityError(err) {
+			errorMessage := "encountered x509 UnknownAuthorityError when connecting to ObjectStore: " +
+				"if using a TLS connection with self-signed certs, you may specify a custom CABundle " +
+				"to mount on the DSP API Server via the DSPA cr under the spec.apiServer.cABundle field; if you have already " +
+				"provided a CABundle, verify the validity of the provided CABundle"
+			log.Info(errorMessage)
+			return false, errors.New(errorMessage)
+		}
+		errorMessage := fmt.Sprintf("Could not connect to (%s), Error: %s", endpoint, err.Error())
+		log.Info(errorMessage)
+		return false, errors.New(errorMessage)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return true, nil
+	case response.StatusCode == http.StatusNotFound:
+		return true, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		errorMessage := fmt.Sprintf("Could not connect to (%s), Error: %s %s", endpoint, response.Status, strings.TrimSpace(string(body)))
+		log.Info(errorMessage)
+		return false, errors.New(errorMessage)
+	}
+}
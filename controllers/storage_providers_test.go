@@ -0,0 +1,260 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createDSPAWithProviderStorage(storage *dspav1.ExternalStorage, secret *corev1.Secret) (*dspav1.DataSciencePipelinesApplication, *DSPAParams, *DSPAReconciler) {
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1.DSPASpec{
+			PodToPodTLS: testutil.BoolPtr(false),
+			APIServer:   &dspav1.APIServer{Deploy: true},
+			MLMD:        &dspav1.MLMD{Deploy: true},
+			Database: &dspav1.Database{
+				MariaDB: &dspav1.MariaDB{Deploy: true},
+			},
+			ObjectStorage: &dspav1.ObjectStorage{ExternalStorage: storage},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	if secret != nil {
+		_ = reconciler.Create(ctx, secret)
+	}
+	return dspa, params, reconciler
+}
+
+func TestDeployGCSExternalStorage(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-creds", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"key.json": []byte(`{"client_email":"dspa@project.iam.gserviceaccount.com"}`)},
+	}
+	dspa, params, reconciler := createDSPAWithProviderStorage(&dspav1.ExternalStorage{
+		Host:                "storage.googleapis.com",
+		Bucket:              "mlpipeline",
+		Scheme:              "https",
+		BasePath:            "dspa",
+		Provider:            "gcs",
+		GCSCredentialSecret: &dspav1.GCSCredentialSecret{SecretName: "gcs-creds", ServiceAccountKey: "key.json"},
+	}, secret)
+	ctx := t.Context()
+
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com", params.ObjectStorageConnection.Endpoint)
+	assert.Nil(t, params.ObjectStorageConnection.CredentialsSecret)
+
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	launcherConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, launcherConfig, "kfp-launcher", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, "gs://mlpipeline/dspa", launcherConfig.Data["defaultPipelineRoot"])
+	assert.Contains(t, launcherConfig.Data["providers"], "tokenKey: key.json")
+
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	workflowControllerConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, workflowControllerConfig, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], "serviceAccountKeySecret:")
+	assert.NotContains(t, workflowControllerConfig.Data["artifactRepository"], "s3:")
+}
+
+func TestDeployAzureExternalStorage(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure-creds", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"accountKey": []byte(base64.StdEncoding.EncodeToString([]byte("key")))},
+	}
+	dspa, params, reconciler := createDSPAWithProviderStorage(&dspav1.ExternalStorage{
+		Host:                  "dspaaccount.blob.core.windows.net",
+		Bucket:                "pipelines",
+		Scheme:                "https",
+		Provider:              "azure",
+		AzureCredentialSecret: &dspav1.AzureCredentialSecret{SecretName: "azure-creds", AccountKey: "accountKey"},
+	}, secret)
+	ctx := t.Context()
+
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+	assert.Equal(t, "dspaaccount", params.ObjectStorageConnection.AzureAccountName)
+
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	launcherConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, launcherConfig, "kfp-launcher", "testnamespace")
+	require.NoError(t, err)
+	assert.Equal(t, "azblob://pipelines", launcherConfig.Data["defaultPipelineRoot"])
+
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	workflowControllerConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, workflowControllerConfig, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], `endpoint: "https://dspaaccount.blob.core.windows.net"`)
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], `container: "pipelines"`)
+}
+
+func TestExtractParamsFailsWithoutGCSServiceAccountKey(t *testing.T) {
+	dspa, params, reconciler := createDSPAWithProviderStorage(&dspav1.ExternalStorage{
+		Host:                "storage.googleapis.com",
+		Bucket:              "mlpipeline",
+		Scheme:              "https",
+		Provider:            "gcs",
+		GCSCredentialSecret: &dspav1.GCSCredentialSecret{SecretName: "missing", ServiceAccountKey: "key.json"},
+	}, nil)
+
+	err := params.ExtractParams(t.Context(), dspa, reconciler.Client, reconciler.Log)
+	assert.Error(t, err)
+}
+
+func TestConnectAndQueryGCSBucket(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	listStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"gcs-token","token_type":"Bearer","expires_in":3600}`))
+		case "/storage/v1/b/mlpipeline/o":
+			assert.Equal(t, "Bearer gcs-token", r.Header.Get("Authorization"))
+			w.WriteHeader(listStatus)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	serviceAccountKey, err := json.Marshal(map[string]string{
+		"client_email": "dspa@project.iam.gserviceaccount.com",
+		"private_key":  string(privateKeyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	verified, err := ConnectAndQueryGCSBucket(t.Context(), logr.Discard(), server.URL, "mlpipeline", serviceAccountKey, nil, nil, 10*time.Second)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+
+	listStatus = http.StatusForbidden
+	verified, err = ConnectAndQueryGCSBucket(t.Context(), logr.Discard(), server.URL, "mlpipeline", serviceAccountKey, nil, nil, 10*time.Second)
+	assert.False(t, verified)
+	assert.ErrorContains(t, err, "403")
+
+	verified, err = ConnectAndQueryGCSBucket(t.Context(), logr.Discard(), server.URL, "mlpipeline", []byte("not-json"), nil, nil, 10*time.Second)
+	assert.False(t, verified)
+	assert.Error(t, err)
+}
+
+func TestConnectAndQueryAzureContainer(t *testing.T) {
+	accountKey := []byte(base64.StdEncoding.EncodeToString([]byte("azure-account-key")))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected, err := signAzureSharedKey(r, "dspaaccount", accountKey)
+		require.NoError(t, err)
+		if r.Header.Get("Authorization") != "SharedKey dspaaccount:"+expected {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if strings.TrimPrefix(r.URL.Path, "/") != "pipelines" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "list", r.URL.Query().Get("comp"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	verified, err := ConnectAndQueryAzureContainer(t.Context(), logr.Discard(), server.URL, "pipelines", "dspaaccount", accountKey, nil, nil, 10*time.Second)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+
+	// A missing container still proves that the endpoint and the account key work
+	verified, err = ConnectAndQueryAzureContainer(t.Context(), logr.Discard(), server.URL, "missing", "dspaaccount", accountKey, nil, nil, 10*time.Second)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+
+	wrongKey := []byte(base64.StdEncoding.EncodeToString([]byte("wrong-key")))
+	verified, err = ConnectAndQueryAzureContainer(t.Context(), logr.Discard(), server.URL, "pipelines", "dspaaccount", wrongKey, nil, nil, 10*time.Second)
+	assert.False(t, verified)
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestSignAzureSharedKey(t *testing.T) {
+	accountKey := []byte(base64.StdEncoding.EncodeToString([]byte("azure-account-key")))
+	request, err := http.NewRequest(http.MethodGet, "https://dspaaccount.blob.core.windows.net/pipelines?restype=container&comp=list", nil)
+	require.NoError(t, err)
+	request.Header.Set("x-ms-date", "Fri, 01 Jan 2027 00:00:00 GMT")
+	request.Header.Set("x-ms-version", azureStorageAPI)
+
+	signature, err := signAzureSharedKey(request, "dspaaccount", accountKey)
+	require.NoError(t, err)
+
+	stringToSign := "GET" + strings.Repeat("\n", 12) +
+		"x-ms-date:Fri, 01 Jan 2027 00:00:00 GMT\n" +
+		"x-ms-version:" + azureStorageAPI + "\n" +
+		"/dspaaccount/pipelines\ncomp:list\nrestype:container"
+	mac := hmac.New(sha256.New, []byte("azure-account-key"))
+	mac.Write([]byte(stringToSign))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), signature)
+
+	_, err = signAzureSharedKey(request, "dspaaccount", []byte("not base64!"))
+	assert.Error(t, err)
+}
+
+func TestExtractParamsRejectsReadWriteHealthCheckWithoutS3(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-creds", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"key.json": []byte(`{"client_email":"dspa@project.iam.gserviceaccount.com"}`)},
+	}
+	dspa, params, reconciler := createDSPAWithProviderStorage(&dspav1.ExternalStorage{
+		Host:                "storage.googleapis.com",
+		Bucket:              "mlpipeline",
+		Scheme:              "https",
+		Provider:            "gcs",
+		GCSCredentialSecret: &dspav1.GCSCredentialSecret{SecretName: "gcs-creds", ServiceAccountKey: "key.json"},
+	}, secret)
+	dspa.Spec.ObjectStorage.HealthCheck = &dspav1.ObjectStorageHealthCheck{Mode: "ReadWrite"}
+
+	err := params.ExtractParams(t.Context(), dspa, reconciler.Client, reconciler.Log)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported by the s3 provider")
+}
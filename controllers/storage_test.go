@@ -21,17 +21,28 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestDeployStorage(t *testing.T) {
@@ -271,7 +282,7 @@ func TestDefaultDeployBehaviorStorage(t *testing.T) {
 
 func TestIsDatabaseAccessibleTrue(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
 		return true, nil
 	}
 
@@ -309,7 +320,7 @@ func TestIsDatabaseAccessibleTrue(t *testing.T) {
 
 func TestIsDatabaseNotAccessibleFalse(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
 		return false, errors.New("Object Store is not Accessible")
 	}
 
@@ -347,7 +358,7 @@ func TestIsDatabaseNotAccessibleFalse(t *testing.T) {
 
 func TestDisabledHealthCheckReturnsTrue(t *testing.T) {
 	// Override the live connection function with a mock version that would always return false if called
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
 		return false, errors.New("Object Store is not Accessible")
 	}
 
@@ -387,7 +398,7 @@ func TestDisabledHealthCheckReturnsTrue(t *testing.T) {
 
 func TestIsDatabaseAccessibleBadAccessKey(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
 		return true, nil
 	}
 
@@ -425,7 +436,7 @@ func TestIsDatabaseAccessibleBadAccessKey(t *testing.T) {
 
 func TestIsDatabaseAccessibleBadSecretKey(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
 		return true, nil
 	}
 
@@ -560,3 +571,478 @@ S9IA40yOaVHMI51Fr1i1EIWvP8oJY8rAPWq45JnfFen3tOqKfw==
 	assert.NotNil(t, err)
 	assert.Nil(t, transport)
 }
+
+func createDSPAWithWebIdentityStorage() *dspav1.DataSciencePipelinesApplication {
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			PodToPodTLS: testutil.BoolPtr(false),
+			APIServer:   &dspav1.APIServer{Deploy: true},
+			MLMD:        &dspav1.MLMD{Deploy: true},
+			Database: &dspav1.Database{
+				MariaDB: &dspav1.MariaDB{Deploy: true},
+			},
+			ObjectStorage: &dspav1.ObjectStorage{
+				ExternalStorage: &dspav1.ExternalStorage{
+					Host:            "s3.us-east-1.amazonaws.com",
+					Bucket:          "mlpipeline",
+					Scheme:          "https",
+					Region:          "us-east-1",
+					CredentialsMode: "WebIdentity",
+					WebIdentity:     &dspav1.WebIdentity{RoleARN: "arn:aws:iam::123456789012:role/dspa"},
+				},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = "testnamespace"
+	return dspa
+}
+
+func TestDeployWebIdentityExternalStorage(t *testing.T) {
+	dspa := createDSPAWithWebIdentityStorage()
+
+	// No credentials Secret is required
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+	assert.Nil(t, params.ObjectStorageConnection.CredentialsSecret)
+	assert.Equal(t, "sts.amazonaws.com", params.ObjectStorageConnection.WebIdentity.Audience)
+
+	// The API Server service account is available to the health check before the API Server is deployed
+	err = reconciler.ReconcileStorage(ctx, dspa, params)
+	assert.Nil(t, err)
+	created, err := reconciler.IsResourceCreated(ctx, &corev1.ServiceAccount{}, "ds-pipeline-testdspa", "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	deployment := &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	container := getDSPipelineAPIServerContainer(deployment)
+	roleARN, _ := getEnvValue(t, container, "AWS_ROLE_ARN")
+	assert.Equal(t, "arn:aws:iam::123456789012:role/dspa", roleARN)
+	tokenFile, _ := getEnvValue(t, container, "AWS_WEB_IDENTITY_TOKEN_FILE")
+	assert.Equal(t, "/var/run/secrets/dspa/serviceaccount/token", tokenFile)
+	_, found := getEnvValue(t, container, "OBJECTSTORECONFIG_ACCESSKEY")
+	assert.False(t, found)
+
+	var tokenProjection *corev1.ServiceAccountTokenProjection
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Name == "web-identity-token" {
+			tokenProjection = volume.Projected.Sources[0].ServiceAccountToken
+		}
+	}
+	if assert.NotNil(t, tokenProjection) {
+		assert.Equal(t, "sts.amazonaws.com", tokenProjection.Audience)
+	}
+
+	// Pipeline runs read their credentials from the environment
+	launcherConfig := &corev1.ConfigMap{}
+	created, err = reconciler.IsResourceCreated(ctx, launcherConfig, "kfp-launcher", "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Contains(t, launcherConfig.Data["providers"], "fromEnv: true")
+
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	assert.Nil(t, err)
+	workflowControllerConfig := &corev1.ConfigMap{}
+	created, err = reconciler.IsResourceCreated(ctx, workflowControllerConfig, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], "useSDKCreds: true")
+	assert.NotContains(t, workflowControllerConfig.Data["artifactRepository"], "accessKeySecret")
+
+	runnerSA := &corev1.ServiceAccount{}
+	_, err = reconciler.IsResourceCreated(ctx, runnerSA, "pipeline-runner-testdspa", "testnamespace")
+	assert.Nil(t, err)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/dspa", runnerSA.Annotations["eks.amazonaws.com/role-arn"])
+}
+
+func TestIsObjectStorageAccessibleWebIdentity(t *testing.T) {
+	var usedCred *credentials.Credentials
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+		usedCred = cred
+		return true, nil
+	}
+
+	dspa := createDSPAWithWebIdentityStorage()
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	verified, err := reconciler.isObjectStorageAccessible(ctx, dspa, params)
+	assert.True(t, verified, err)
+	assert.NotNil(t, usedCred)
+}
+
+func TestGetSTSEndpoint(t *testing.T) {
+	tests := map[string]struct {
+		region   string
+		expected string
+	}{
+		"no region":        {region: "", expected: "https://sts.amazonaws.com"},
+		"auto region":      {region: "auto", expected: "https://sts.amazonaws.com"},
+		"aws region":       {region: "eu-west-1", expected: "https://sts.eu-west-1.amazonaws.com"},
+		"aws china region": {region: "cn-north-1", expected: "https://sts.cn-north-1.amazonaws.com.cn"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getSTSEndpoint(test.region))
+	}
+}
+
+func TestIsObjectStorageAccessibleReadWrite(t *testing.T) {
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+		return false, errors.New("the read-only health check should not run in ReadWrite mode")
+	}
+	connectAndProbeObjStore := ConnectAndProbeObjStore
+	defer func() { ConnectAndProbeObjStore = connectAndProbeObjStore }()
+	var probedObject string
+	ConnectAndProbeObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket, objectName string, cred *credentials.Credentials, sse encrypt.ServerSide, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+		probedObject = objectName
+		return false, &ObjectStoreHealthCheckError{Reason: config.MissingWritePermission, Err: errors.New("access denied")}
+	}
+
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			ObjectStorage: &dspav1.ObjectStorage{
+				HealthCheck: &dspav1.ObjectStorageHealthCheck{Mode: config.ObjectStorageHealthCheckModeReadWrite},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = "testnamespace"
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	SecureConnection := false
+	params := &DSPAParams{
+		Name: "testdspa",
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "foo",
+			Port:            "1337",
+			BasePath:        "some/path",
+			Secure:          &SecureConnection,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+
+	verified, err := reconciler.isObjectStorageAccessible(ctx, dspa, params)
+	assert.False(t, verified)
+	assert.Equal(t, "some/path/.dspa-health-check-testdspa", probedObject)
+	assert.Equal(t, config.MissingWritePermission, objectStoreHealthCheckReason(err))
+	assert.Equal(t, config.FailingToDeploy, objectStoreHealthCheckReason(errors.New("connection refused")))
+}
+
+func TestConnectAndProbeObjStore(t *testing.T) {
+	content := "Data Science Pipelines object storage health check"
+	deniedMethod := ""
+	bucketExists := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError := func(status int, code string) {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(status)
+			_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+		}
+		if !bucketExists {
+			writeError(http.StatusNotFound, "NoSuchBucket")
+			return
+		}
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+			return
+		}
+		assert.Equal(t, "/mlpipeline/some/path/.dspa-health-check-testdspa", r.URL.Path)
+		if r.Method == deniedMethod {
+			writeError(http.StatusForbidden, "AccessDenied")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			w.Header().Set("ETag", `"probe"`)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Header().Set("ETag", `"probe"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			_, _ = w.Write([]byte(content))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	probe := func() (bool, error) {
+		return ConnectAndProbeObjStore(t.Context(), logr.Discard(), strings.TrimPrefix(server.URL, "http://"), "mlpipeline",
+			"some/path/.dspa-health-check-testdspa", credentials.NewStaticV4("fooaccesskey", "foosecretkey", ""),
+			nil, false, nil, nil, 10*time.Second)
+	}
+
+	verified, err := probe()
+	assert.True(t, verified)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		deniedMethod   string
+		expectedReason string
+	}{
+		"missing write permission":  {deniedMethod: http.MethodPut, expectedReason: config.MissingWritePermission},
+		"missing read permission":   {deniedMethod: http.MethodGet, expectedReason: config.MissingReadPermission},
+		"missing delete permission": {deniedMethod: http.MethodDelete, expectedReason: config.MissingDeletePermission},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			deniedMethod = test.deniedMethod
+			verified, err := probe()
+			assert.False(t, verified)
+			assert.Equal(t, test.expectedReason, objectStoreHealthCheckReason(err))
+		})
+	}
+
+	deniedMethod = ""
+	bucketExists = false
+	verified, err = probe()
+	assert.False(t, verified)
+	assert.Equal(t, config.BucketNotFound, objectStoreHealthCheckReason(err))
+}
+
+func TestObjectStorageHealthCheckMode(t *testing.T) {
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		Spec: dspav1.DSPASpec{
+			ObjectStorage: &dspav1.ObjectStorage{
+				HealthCheck: &dspav1.ObjectStorageHealthCheck{Mode: config.ObjectStorageHealthCheckModeReadWrite},
+			},
+		},
+	}
+	params := &DSPAParams{}
+	assert.Equal(t, config.ObjectStorageHealthCheckModeReadWrite, params.ObjectStorageHealthCheckMode(dspa))
+
+	dspa.Spec.ObjectStorage.HealthCheck = nil
+	assert.Equal(t, config.ObjectStorageHealthCheckModeReadOnly, params.ObjectStorageHealthCheckMode(dspa))
+}
+
+func TestDeployExternalStorageEncryption(t *testing.T) {
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: "testnamespace"},
+			Data: map[string][]byte{
+				"accesskey": []byte("fooaccesskey"),
+				"secretkey": []byte("foosecretkey"),
+			},
+		}
+	}
+	storage := &dspav1.ExternalStorage{
+		Host:               "s3.amazonaws.com",
+		Bucket:             "mlpipeline",
+		Scheme:             "https",
+		S3CredentialSecret: &dspav1.S3CredentialSecret{SecretName: "s3-creds", AccessKey: "accesskey", SecretKey: "secretkey"},
+		Encryption: &dspav1.ObjectStorageEncryption{
+			Type:     config.ObjectStorageEncryptionSSEKMS,
+			KMSKeyID: "arn:aws:kms:us-east-1:123456789012:key/dspa",
+		},
+	}
+	dspa, params, reconciler := createDSPAWithProviderStorage(storage, newSecret())
+	ctx := t.Context()
+
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	deployment := &appsv1.Deployment{}
+	_, err = reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	// The KFP API server and launcher have no server-side encryption settings
+	for _, env := range getDSPipelineAPIServerContainer(deployment).Env {
+		assert.False(t, strings.HasPrefix(env.Name, "OBJECTSTORECONFIG_SSE"), env.Name)
+	}
+
+	launcherConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, launcherConfig, "kfp-launcher", "testnamespace")
+	require.NoError(t, err)
+	assert.NotContains(t, launcherConfig.Data["providers"], "encryption")
+
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	workflowControllerConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, workflowControllerConfig, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], "enableEncryption: true")
+	assert.Contains(t, workflowControllerConfig.Data["artifactRepository"], `kmsKeyId: "arn:aws:kms:us-east-1:123456789012:key/dspa"`)
+
+	// SSE-C is refused for the DSPAs stored before it was removed from the CRD
+	storage.Encryption = &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSEC}
+	dspa, params, reconciler = createDSPAWithProviderStorage(storage, newSecret())
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not supported")
+}
+
+func TestConnectAndProbeObjStoreEncryption(t *testing.T) {
+	var encryptionHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			_, _ = w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`))
+			return
+		}
+		encryptionHeaders = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("<Error><Code>KMS.NotFoundException</Code><Message>Invalid keyId</Message></Error>"))
+	}))
+	defer server.Close()
+
+	sse, err := getObjStoreServerSideEncryption(&DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Encryption: &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSEKMS, KMSKeyID: "missing-key"},
+		},
+	})
+	require.NoError(t, err)
+
+	verified, err := ConnectAndProbeObjStore(t.Context(), logr.Discard(), strings.TrimPrefix(server.URL, "http://"), "mlpipeline",
+		".dspa-health-check-testdspa", credentials.NewStaticV4("fooaccesskey", "foosecretkey", ""), sse,
+		false, nil, nil, 10*time.Second)
+	assert.False(t, verified)
+	assert.Equal(t, config.EncryptionMisconfigured, objectStoreHealthCheckReason(err))
+	assert.Equal(t, "aws:kms", encryptionHeaders.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "missing-key", encryptionHeaders.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+}
+
+func TestGetObjStoreServerSideEncryption(t *testing.T) {
+	params := &DSPAParams{}
+	sse, err := getObjStoreServerSideEncryption(params)
+	assert.NoError(t, err)
+	assert.Nil(t, sse)
+
+	params.ObjectStorageConnection.Encryption = &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSES3}
+	sse, err = getObjStoreServerSideEncryption(params)
+	require.NoError(t, err)
+	assert.Equal(t, encrypt.S3, sse.Type())
+
+	params.ObjectStorageConnection.Encryption = &dspav1.ObjectStorageEncryption{Type: config.ObjectStorageEncryptionSSEKMS, KMSKeyID: "dspa"}
+	sse, err = getObjStoreServerSideEncryption(params)
+	require.NoError(t, err)
+	assert.Equal(t, encrypt.KMS, sse.Type())
+}
+
+func TestDeployAdditionalObjectStores(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: "testnamespace"},
+		Data: map[string][]byte{
+			"accesskey": []byte("fooaccesskey"),
+			"secretkey": []byte("foosecretkey"),
+		},
+	}
+	storage := &dspav1.ExternalStorage{
+		Host:               "s3.amazonaws.com",
+		Bucket:             "mlpipeline",
+		Scheme:             "https",
+		S3CredentialSecret: &dspav1.S3CredentialSecret{SecretName: "s3-creds", AccessKey: "accesskey", SecretKey: "secretkey"},
+	}
+	dspa, params, reconciler := createDSPAWithProviderStorage(storage, secret)
+	dspa.Spec.ObjectStorage.AdditionalStores = []dspav1.AdditionalObjectStore{
+		{
+			Name:               "datasets",
+			Host:               "minio.datasets.svc",
+			Port:               "9000",
+			Scheme:             "http",
+			Bucket:             "datasets",
+			PipelineRootPrefix: "/team-a/",
+			S3CredentialSecret: &dspav1.S3CredentialSecret{SecretName: "s3-creds", AccessKey: "accesskey", SecretKey: "secretkey"},
+		},
+		{
+			Name:               "missing-creds",
+			Host:               "s3.us-west-2.amazonaws.com",
+			Bucket:             "archive",
+			S3CredentialSecret: &dspav1.S3CredentialSecret{SecretName: "archive-creds", AccessKey: "accesskey", SecretKey: "secretkey"},
+		},
+	}
+	ctx := t.Context()
+
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	require.NoError(t, err)
+	stores := params.ObjectStorageConnection.AdditionalStores
+	require.Len(t, stores, 2)
+	assert.Equal(t, "http://minio.datasets.svc:9000", stores[0].Endpoint)
+	assert.Equal(t, "team-a", stores[0].PipelineRootPrefix)
+	assert.False(t, stores[0].Secure)
+	assert.Equal(t, "https://s3.us-west-2.amazonaws.com", stores[1].Endpoint)
+	assert.Equal(t, "auto", stores[1].Region)
+	assert.Empty(t, stores[1].AccessKeyID)
+
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	launcherConfig := &corev1.ConfigMap{}
+	_, err = reconciler.IsResourceCreated(ctx, launcherConfig, "kfp-launcher", "testnamespace")
+	require.NoError(t, err)
+	providers := launcherConfig.Data["providers"]
+	assert.Contains(t, providers, "overrides:")
+	assert.Contains(t, providers, "bucketName: datasets")
+	assert.Contains(t, providers, "keyPrefix: team-a")
+	assert.Contains(t, providers, "endpoint: http://minio.datasets.svc:9000")
+	assert.Contains(t, providers, "disableSSL: true")
+	assert.Contains(t, providers, "bucketName: archive")
+	assert.Contains(t, providers, "secretName: archive-creds")
+
+	// The launcher config is either generated or provided by the user
+	require.NoError(t, reconciler.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-launcher-config", Namespace: "testnamespace"},
+		Data:       map[string]string{"defaultPipelineRoot": "s3://mlpipeline"},
+	}))
+	dspa.Spec.APIServer.CustomKfpLauncherConfigMap = "my-launcher-config"
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.ErrorContains(t, err, "cannot be combined with apiServer customKfpLauncherConfigMap")
+}
+
+func TestCheckAdditionalObjectStores(t *testing.T) {
+	connectAndQueryObjStore := ConnectAndQueryObjStore
+	defer func() { ConnectAndQueryObjStore = connectAndQueryObjStore }()
+	var queriedBuckets []string
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, cred *credentials.Credentials, secure bool, pemCerts [][]byte, proxyConfig *dspav1.ProxyConfig, objStoreConnectionTimeout time.Duration) (bool, error) {
+		queriedBuckets = append(queriedBuckets, bucket)
+		if bucket == "unreachable" {
+			return false, errors.New("connection refused")
+		}
+		return true, nil
+	}
+
+	dspa := &dspav1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec:       dspav1.DSPASpec{ObjectStorage: &dspav1.ObjectStorage{}},
+	}
+	_, _, reconciler := CreateNewTestObjects()
+	credentialsSecret := &dspav1.S3CredentialSecret{SecretName: "s3-creds", AccessKey: "accesskey", SecretKey: "secretkey"}
+	accessKey := base64.StdEncoding.EncodeToString([]byte("fooaccesskey"))
+	secretKey := base64.StdEncoding.EncodeToString([]byte("foosecretkey"))
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			AdditionalStores: []AdditionalObjectStoreConnection{
+				{Name: "datasets", Host: "foo", Bucket: "datasets", CredentialsSecret: credentialsSecret, AccessKeyID: accessKey, SecretAccessKey: secretKey},
+				{Name: "unreachable", Host: "bar", Bucket: "unreachable", CredentialsSecret: credentialsSecret, AccessKeyID: accessKey, SecretAccessKey: secretKey},
+				{Name: "missing-creds", Host: "baz", Bucket: "archive", CredentialsSecret: credentialsSecret},
+			},
+		},
+	}
+
+	status := reconciler.checkAdditionalObjectStores(t.Context(), dspa, params)
+	require.NotNil(t, status)
+	assert.Equal(t, []string{"datasets", "unreachable"}, queriedBuckets)
+	assert.Equal(t, dspav1.AdditionalObjectStoreStatus{Name: "datasets", Available: true}, status.AdditionalStores[0])
+	assert.Equal(t, dspav1.AdditionalObjectStoreStatus{Name: "unreachable", Available: false, Message: "connection refused"}, status.AdditionalStores[1])
+	assert.False(t, status.AdditionalStores[2].Available)
+	assert.Contains(t, status.AdditionalStores[2].Message, "s3-creds")
+
+	// Stores are assumed available when the health check is disabled
+	queriedBuckets = nil
+	dspa.Spec.ObjectStorage.DisableHealthCheck = true
+	status = reconciler.checkAdditionalObjectStores(t.Context(), dspa, params)
+	assert.Empty(t, queriedBuckets)
+	for _, store := range status.AdditionalStores {
+		assert.True(t, store.Available)
+	}
+
+	assert.Nil(t, reconciler.checkAdditionalObjectStores(t.Context(), dspa, &DSPAParams{}))
+}
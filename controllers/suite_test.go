@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	buildv1 "github.com/openshift/api/build/v1"
 	imagev1 "github.com/openshift/api/image/v1"
@@ -76,7 +77,7 @@ func (s *ControllerSuite) SetupTest() {
 	ConnectAndQueryDatabase = func(
 		host string,
 		log logr.Logger,
-		port, username, password, dbname, tls string,
+		driver, port, username, password, dbname, tls string,
 		dbConnectionTimeout time.Duration,
 		pemCerts [][]byte,
 		extraParams map[string]string) (bool, error) {
@@ -86,13 +87,33 @@ func (s *ControllerSuite) SetupTest() {
 		ctx context.Context,
 		log logr.Logger,
 		endpoint, bucket string,
-		accesskey, secretkey []byte,
+		cred *credentials.Credentials,
 		secure bool,
 		pemCerts [][]byte,
 		proxyConfig *dspav1.ProxyConfig,
 		objStoreConnectionTimeout time.Duration) (bool, error) {
 		return true, nil
 	}
+	ConnectAndQueryGCSBucket = func(
+		ctx context.Context,
+		log logr.Logger,
+		endpoint, bucket string,
+		serviceAccountKey []byte,
+		pemCerts [][]byte,
+		proxyConfig *dspav1.ProxyConfig,
+		objStoreConnectionTimeout time.Duration) (bool, error) {
+		return true, nil
+	}
+	ConnectAndQueryAzureContainer = func(
+		ctx context.Context,
+		log logr.Logger,
+		endpoint, container, accountName string,
+		accountKey []byte,
+		pemCerts [][]byte,
+		proxyConfig *dspav1.ProxyConfig,
+		objStoreConnectionTimeout time.Duration) (bool, error) {
+		return true, nil
+	}
 }
 
 func (s *ControllerSuite) SetupSuite() {
@@ -146,6 +167,10 @@ func (s *ControllerSuite) SetupSuite() {
 		Log:           ctrl.Log.WithName("controllers").WithName("ds-pipelines-controller"),
 		Scheme:        scheme.Scheme,
 		TemplatesPath: "../config/internal/",
+		ClusterCapabilities: ClusterCapabilities{
+			Routes:    true,
+			Ingresses: true,
+		},
 	}).SetupWithManager(mgr)
 	assert.NoError(s.T(), err)
 
@@ -0,0 +1,160 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	configv1 "github.com/openshift/api/config/v1"
+	libgocrypto "github.com/openshift/library-go/pkg/crypto"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// envoyTLSVersions maps the TLS versions of the TLS security profiles to the Envoy TLS protocol versions
+var envoyTLSVersions = map[configv1.TLSProtocolVersion]string{
+	configv1.VersionTLS10: "TLSv1_0",
+	configv1.VersionTLS11: "TLSv1_1",
+	configv1.VersionTLS12: "TLSv1_2",
+	configv1.VersionTLS13: "TLSv1_3",
+}
+
+// envoyCipherSuites are the TLS 1.2 cipher suites of the TLS security profiles supported by Envoy. Envoy rejects its
+// configuration when it contains an unsupported cipher suite, and does not allow to configure the TLS 1.3 ones.
+var envoyCipherSuites = map[string]bool{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": true,
+	"ECDHE-RSA-AES128-GCM-SHA256":   true,
+	"ECDHE-ECDSA-AES256-GCM-SHA384": true,
+	"ECDHE-RSA-AES256-GCM-SHA384":   true,
+	"ECDHE-ECDSA-CHACHA20-POLY1305": true,
+	"ECDHE-RSA-CHACHA20-POLY1305":   true,
+	"ECDHE-ECDSA-AES128-SHA":        true,
+	"ECDHE-RSA-AES128-SHA":          true,
+	"ECDHE-ECDSA-AES256-SHA":        true,
+	"ECDHE-RSA-AES256-SHA":          true,
+	"AES128-GCM-SHA256":             true,
+	"AES256-GCM-SHA384":             true,
+	"AES128-SHA":                    true,
+	"AES256-SHA":                    true,
+}
+
+// TLSProfile is the TLS security profile of the cluster, rendered into the TLS configuration of the DSPA components.
+// It is updated by the TLS security profile watcher while the operator runs.
+type TLSProfile struct {
+	mu   sync.RWMutex
+	spec configv1.TLSProfileSpec
+	// events re-reconciles every DSPA when the profile changes
+	events chan event.GenericEvent
+}
+
+// NewTLSProfile returns a TLSProfile initialized with the given profile
+func NewTLSProfile(spec configv1.TLSProfileSpec) *TLSProfile {
+	return &TLSProfile{
+		spec:   spec,
+		events: make(chan event.GenericEvent),
+	}
+}
+
+// Get returns the current TLS profile. The Intermediate profile is used when no profile was set, like on clusters
+// without the OpenShift config API.
+func (t *TLSProfile) Get() configv1.TLSProfileSpec {
+	if t == nil {
+		return *configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.spec
+}
+
+// Set updates the TLS profile and reports whether it changed
+func (t *TLSProfile) Set(spec configv1.TLSProfileSpec) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if reflect.DeepEqual(t.spec, spec) {
+		return false
+	}
+	t.spec = spec
+	return true
+}
+
+// OnProfileChange updates the TLS profile and re-reconciles every DSPA, so the components are rolled out with the new
+// profile. The DSPAs are enqueued in the background since the DSPA controller only runs on the leader.
+func (r *DSPAReconciler) OnProfileChange(ctx context.Context, spec configv1.TLSProfileSpec) error {
+	if !r.TLSProfile.Set(spec) {
+		return nil
+	}
+
+	var dspaList dspav1.DataSciencePipelinesApplicationList
+	if err := r.List(ctx, &dspaList); err != nil {
+		return err
+	}
+	var events []event.GenericEvent
+	for i := range dspaList.Items {
+		if util.DSPAWithSupportedDSPVersion(&dspaList.Items[i]) {
+			events = append(events, event.GenericEvent{Object: &dspaList.Items[i]})
+		}
+	}
+	r.Log.Info("TLS profile changed, reconciling all DSPAs", "minTLSVersion", spec.MinTLSVersion, "dspas", len(events))
+	go func() {
+		for _, e := range events {
+			select {
+			case r.TLSProfile.events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// TLSProfileParams is the TLS security profile in the formats of the components. The cipher suites are empty when the
+// minimum version is TLS 1.3, since the TLS 1.3 cipher suites cannot be configured. The listeners of the KFP API server
+// and pipelineversions webhook are not covered, their binary has no TLS version or cipher flags.
+type TLSProfileParams struct {
+	// MinVersion and CipherSuites are in the format of the kube-rbac-proxy flags, the cipher suites are IANA names
+	MinVersion   string
+	CipherSuites string
+	// EnvoyMinVersion and EnvoyCipherSuites are in the format of the Envoy TLS parameters, the cipher suites are
+	// OpenSSL names
+	EnvoyMinVersion   string
+	EnvoyCipherSuites []string
+	// EnvoyConfigHash restarts Envoy when its TLS parameters change, since it does not reload its configuration
+	EnvoyConfigHash string
+}
+
+// SetupTLSProfile resolves the TLS security profile rendered into the TLS configuration of the components
+func (p *DSPAParams) SetupTLSProfile(spec configv1.TLSProfileSpec) {
+	p.TLSProfile = TLSProfileParams{
+		MinVersion:      string(spec.MinTLSVersion),
+		EnvoyMinVersion: envoyTLSVersions[spec.MinTLSVersion],
+	}
+	if spec.MinTLSVersion != configv1.VersionTLS13 {
+		p.TLSProfile.CipherSuites = strings.Join(libgocrypto.OpenSSLToIANACipherSuites(spec.Ciphers), ",")
+		for _, cipher := range spec.Ciphers {
+			if envoyCipherSuites[cipher] {
+				p.TLSProfile.EnvoyCipherSuites = append(p.TLSProfile.EnvoyCipherSuites, cipher)
+			}
+		}
+	}
+	envoyConfig := p.TLSProfile.EnvoyMinVersion + ":" + strings.Join(p.TLSProfile.EnvoyCipherSuites, ",")
+	p.TLSProfile.EnvoyConfigHash = fmt.Sprintf("%x", sha256.Sum256([]byte(envoyConfig)))
+}
@@ -0,0 +1,174 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetupTLSProfile(t *testing.T) {
+	params := &DSPAParams{}
+	params.SetupTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileIntermediateType])
+	assert.Equal(t, "VersionTLS12", params.TLSProfile.MinVersion)
+	assert.Contains(t, params.TLSProfile.CipherSuites, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	assert.Equal(t, "TLSv1_2", params.TLSProfile.EnvoyMinVersion)
+	assert.Contains(t, params.TLSProfile.EnvoyCipherSuites, "ECDHE-RSA-AES128-GCM-SHA256")
+	// Envoy does not allow to configure the TLS 1.3 cipher suites
+	assert.NotContains(t, params.TLSProfile.EnvoyCipherSuites, "TLS_AES_128_GCM_SHA256")
+	intermediateHash := params.TLSProfile.EnvoyConfigHash
+
+	// The cipher suites cannot be configured when the minimum version is TLS 1.3
+	params.SetupTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileModernType])
+	assert.Equal(t, TLSProfileParams{
+		MinVersion:      "VersionTLS13",
+		EnvoyMinVersion: "TLSv1_3",
+		EnvoyConfigHash: params.TLSProfile.EnvoyConfigHash,
+	}, params.TLSProfile)
+	assert.NotEqual(t, intermediateHash, params.TLSProfile.EnvoyConfigHash)
+
+	// Envoy rejects the cipher suites it does not support
+	params.SetupTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileOldType])
+	assert.NotContains(t, params.TLSProfile.EnvoyCipherSuites, "DES-CBC3-SHA")
+}
+
+func TestDeployAPIServerTLSProfile(t *testing.T) {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.PodToPodTLS = testutil.BoolPtr(false)
+	dspa.Spec.APIServer.EnableRoute = true
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	params.SetupTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileModernType])
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	var proxyArgs []string
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "kube-rbac-proxy" {
+			proxyArgs = container.Args
+		}
+	}
+	assert.Contains(t, proxyArgs, "--tls-min-version=VersionTLS13")
+	for _, arg := range proxyArgs {
+		assert.NotContains(t, arg, "--tls-cipher-suites")
+	}
+}
+
+func TestDeployWebhookTLSProfile(t *testing.T) {
+	t.Setenv("DSPO_NAMESPACE", testDSPONamespace)
+	dspa := testutil.CreateTestDSPA()
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	params.SetupTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileModernType])
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.Client.Create(ctx, testutil.CreateTestDSPODeployment(params.DSPONamespace)))
+	require.NoError(t, reconciler.ReconcileWebhook(ctx, params))
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, testWebhookName, testDSPONamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	// The webhook only listens on the loopback interface, behind the kube-rbac-proxy following the profile
+	var webhookArgs, proxyArgs []string
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		switch container.Name {
+		case "ds-pipeline-webhook":
+			webhookArgs = container.Args
+		case "kube-rbac-proxy":
+			proxyArgs = container.Args
+		}
+	}
+	assert.Contains(t, webhookArgs, "--webhookPortFlag=127.0.0.1:8442")
+	assert.Contains(t, proxyArgs, "--secure-listen-address=0.0.0.0:8443")
+	assert.Contains(t, proxyArgs, "--tls-min-version=VersionTLS13")
+	serviceName := testWebhookName + "." + testDSPONamespace + ".svc"
+	assert.Contains(t, proxyArgs, "--upstream=https://"+serviceName+":8442")
+	require.Len(t, deployment.Spec.Template.Spec.HostAliases, 1)
+	assert.Equal(t, "127.0.0.1", deployment.Spec.Template.Spec.HostAliases[0].IP)
+	assert.Equal(t, []string{serviceName}, deployment.Spec.Template.Spec.HostAliases[0].Hostnames)
+}
+
+func TestDeployMLMDEnvoyTLSProfile(t *testing.T) {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	dspa.Spec.APIServer = nil
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	serviceCA := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.OpenshiftServiceCAConfigMapName, Namespace: "testnamespace"},
+		Data:       map[string]string{config.OpenshiftServiceCAConfigMapKey: "service-ca"},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, serviceCA))
+	grpcCertificate := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-metadata-grpc-tls-certs-testdspa", Namespace: "testnamespace"},
+		Type:       corev1.SecretTypeTLS,
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, grpcCertificate))
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.NoError(t, reconciler.ReconcileMLMD(ctx, dspa, params))
+
+	configMap := &corev1.ConfigMap{}
+	created, err := reconciler.IsResourceCreated(ctx, configMap, "ds-pipeline-metadata-envoy-config-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Contains(t, configMap.Data["envoy.yaml"], "tls_minimum_protocol_version: TLSv1_2")
+	assert.Contains(t, configMap.Data["envoy.yaml"], "- ECDHE-RSA-AES128-GCM-SHA256")
+
+	deployment := &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-metadata-envoy-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, params.TLSProfile.EnvoyConfigHash, deployment.Spec.Template.Annotations["tlsConfigHash"])
+}
+
+func TestOnProfileChange(t *testing.T) {
+	dspa := testutil.CreateDSPAWithAPIServerPodtoPodTlsEnabled()
+	ctx, _, reconciler := CreateNewTestObjects()
+	require.NoError(t, reconciler.Client.Create(ctx, dspa))
+	reconciler.TLSProfile = NewTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileIntermediateType])
+
+	modern := *configv1.TLSProfiles[configv1.TLSProfileModernType]
+	require.NoError(t, reconciler.OnProfileChange(ctx, modern))
+	assert.Equal(t, modern, reconciler.TLSProfile.Get())
+	select {
+	case e := <-reconciler.TLSProfile.events:
+		assert.Equal(t, "testdspa", e.Object.GetName())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the DSPA was not reconciled after the TLS profile change")
+	}
+
+	// The DSPAs are not reconciled again when the profile is unchanged
+	require.NoError(t, reconciler.OnProfileChange(ctx, modern))
+	select {
+	case e := <-reconciler.TLSProfile.events:
+		t.Fatalf("unexpected reconcile of %s", e.Object.GetName())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -34,6 +34,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -64,6 +65,10 @@ func BoolPointer(b bool) *bool {
 	return &b
 }
 
+func Int32Pointer(i int32) *int32 {
+	return &i
+}
+
 func GetTemplatesInDir(templatesDirectory, componentSubdirectory string) ([]string, error) {
 	files, err := os.ReadDir(templatesDirectory + componentSubdirectory)
 	if err != nil {
@@ -175,6 +180,29 @@ func GetRouteHostname(ctx context.Context, routeName, ns string, client client.C
 	return routeHostname, nil
 }
 
+func GetIngressHostname(ctx context.Context, ingressName, ns string, client client.Client) (string, error) {
+	ingressHostname := ""
+	ingress := &networkingv1.Ingress{}
+	namespacedNamed := types.NamespacedName{Name: ingressName, Namespace: ns}
+	err := client.Get(ctx, namespacedNamed, ingress)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if len(ingress.Spec.Rules) > 0 && ingress.Spec.Rules[0].Host != "" {
+		scheme := "http"
+		if len(ingress.Spec.TLS) > 0 {
+			scheme = "https"
+		}
+
+		ingressHostname = scheme + "://" + ingress.Spec.Rules[0].Host
+	}
+	return ingressHostname, nil
+}
+
 func GetServiceIfAvailable(ctx context.Context, svcName, ns string, client client.Client) (bool, *v1.Service, error) {
 	service := &v1.Service{}
 	namespacedNamed := types.NamespacedName{Name: svcName, Namespace: ns}
@@ -258,6 +286,63 @@ func AddLabelTransformer(labelKey, labelValue string) mf.Transformer {
 	}
 }
 
+// AddCommonMetadataTransformer adds the labels and annotations to the metadata of every resource, without overriding
+// the labels and annotations the resource already has.
+func AddCommonMetadataTransformer(labels, annotations map[string]string) mf.Transformer {
+	return func(mfObj *unstructured.Unstructured) error {
+		if len(labels) > 0 {
+			mfObj.SetLabels(mergeMissingKeys(mfObj.GetLabels(), labels))
+		}
+		if len(annotations) > 0 {
+			mfObj.SetAnnotations(mergeMissingKeys(mfObj.GetAnnotations(), annotations))
+		}
+		return nil
+	}
+}
+
+// AddDeploymentPodMetadataTransformer adds the labels and annotations to the pod template of Deployments, without
+// overriding the labels and annotations the pod template already has.
+func AddDeploymentPodMetadataTransformer(labels, annotations map[string]string) mf.Transformer {
+	return func(mfObj *unstructured.Unstructured) error {
+		if mfObj.GetKind() != "Deployment" {
+			return nil
+		}
+		fields := []struct {
+			values map[string]string
+			path   []string
+		}{
+			{labels, []string{"spec", "template", "metadata", "labels"}},
+			{annotations, []string{"spec", "template", "metadata", "annotations"}},
+		}
+		for _, field := range fields {
+			if len(field.values) == 0 {
+				continue
+			}
+			existing, _, err := unstructured.NestedStringMap(mfObj.Object, field.path...)
+			if err != nil {
+				return err
+			}
+			err = unstructured.SetNestedStringMap(mfObj.Object, mergeMissingKeys(existing, field.values), field.path...)
+			if err != nil {
+				return fmt.Errorf("failed to set pod metadata: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+func mergeMissingKeys(existing, additional map[string]string) map[string]string {
+	if existing == nil {
+		existing = make(map[string]string, len(additional))
+	}
+	for key, value := range additional {
+		if _, found := existing[key]; !found {
+			existing[key] = value
+		}
+	}
+	return existing
+}
+
 func AddDeploymentPodLabelTransformer(labelKey, labelValue string) mf.Transformer {
 	return func(mfObj *unstructured.Unstructured) error {
 		// Check if the resource is a Deployment
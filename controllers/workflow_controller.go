@@ -17,17 +17,26 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/go-logr/logr"
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var workflowControllerTemplatesDir = "workflow-controller"
 
-const workflowControllerDefaultResourceNamePrefix = "ds-pipeline-workflow-controller-"
+const (
+	workflowControllerDefaultResourceNamePrefix = "ds-pipeline-workflow-controller-"
+	// argoControllerInstanceIDLabel selects the workflow controller running a workflow, the workflow controllers
+	// with an instanceID only run the workflows with this label set to their instanceID
+	argoControllerInstanceIDLabel = "workflows.argoproj.io/controller-instanceid"
+)
 
 type ArgoWorkflowsControllersConfig struct {
 	ManagementState string `json:"managementState"`
@@ -40,11 +49,8 @@ func (c *ArgoWorkflowsControllersConfig) GetManagementState() string {
 	return c.ManagementState
 }
 
-func (r *DSPAReconciler) ReconcileWorkflowController(dsp *dspav1.DataSciencePipelinesApplication,
-	params *DSPAParams) (bool, error) {
-
-	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
-
+// getArgoWorkflowsControllersConfig returns the management state of the WorkflowControllers of the DSPAs
+func getArgoWorkflowsControllersConfig(log logr.Logger) ArgoWorkflowsControllersConfig {
 	// Get the management state for the WorkflowController subcomponent from the config
 	// Expected format example: {"managementState":"Managed"}
 	dspoArgoWorkflowsControllersJSON := strings.Trim(
@@ -60,6 +66,59 @@ func (r *DSPAReconciler) ReconcileWorkflowController(dsp *dspav1.DataSciencePipe
 			ManagementState: config.DefaultArgoWorkflowsControllersManagementState,
 		}
 	}
+	return argoWorkflowsControllersConfig
+}
+
+// SetupWorkflowControllerInstanceID assigns the Argo instanceID of the WorkflowController deployed for the DSPA when
+// its workflows are isolated from the other workflow controllers of the cluster
+func (p *DSPAParams) SetupWorkflowControllerInstanceID(dsp *dspav1.DataSciencePipelinesApplication, log logr.Logger) {
+	p.WorkflowControllerInstanceID = isolatedWorkflowControllerInstanceID(dsp, log)
+}
+
+// isolatedWorkflowControllerInstanceID returns the instanceID of the WorkflowController deployed for the DSPA, or an
+// empty string when its workflows are not isolated
+func isolatedWorkflowControllerInstanceID(dsp *dspav1.DataSciencePipelinesApplication, log logr.Logger) string {
+	workflowController := dsp.Spec.WorkflowController
+	if workflowController == nil || !workflowController.InstanceIDIsolation || !workflowController.Deploy ||
+		usesExternalWorkflowController(dsp) || !dsp.DeletionTimestamp.IsZero() {
+		return ""
+	}
+	argoWorkflowsControllersConfig := getArgoWorkflowsControllersConfig(log)
+	if argoWorkflowsControllersConfig.GetManagementState() != "Managed" {
+		return ""
+	}
+	return workflowControllerInstanceID(dsp.Namespace, dsp.Name)
+}
+
+// workflowControllerInstanceID returns an instanceID unique to the DSPA that is a valid label value
+func workflowControllerInstanceID(namespace, name string) string {
+	instanceID := namespace + "." + name
+	if len(instanceID) <= validation.LabelValueMaxLength {
+		return instanceID
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(instanceID)))[:16]
+	return strings.TrimRight(instanceID[:validation.LabelValueMaxLength-len(hash)-1], "-.") + "-" + hash
+}
+
+func (r *DSPAReconciler) ReconcileWorkflowController(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, error) {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	argoWorkflowsControllersConfig := getArgoWorkflowsControllersConfig(log)
+
+	// The certificate of the webhook labeling the workflows of the recurring runs is issued by the OpenShift service CA
+	if params.WorkflowControllerInstanceID != "" && !r.ClusterCapabilities.Routes {
+		return false, invalidWorkflowControllerConfig("instanceIDIsolation requires the OpenShift service CA, which " +
+			"issues the certificate of the webhook labeling the workflows")
+	}
+
+	// The pipelines run with the Argo Workflows installation of the cluster, a WorkflowController deployed before
+	// switching to the External mode is removed
+	if usesExternalWorkflowController(dsp) {
+		log.Info("Removing WorkflowController Resources (if present), using an external Argo Workflows installation")
+		return false, r.DeleteResourceDir(params, workflowControllerTemplatesDir)
+	}
 
 	// Conditionally deploy the WorkflowController resource depending on the speciified management state
 	// Managed (or blank) - deploy the WorkflowController subcomponent
@@ -73,9 +132,15 @@ func (r *DSPAReconciler) ReconcileWorkflowController(dsp *dspav1.DataSciencePipe
 			return workflowControllerEnabled, nil
 		}
 
-		log.Info("Applying WorkflowController Resources")
 		workflowControllerEnabled = true
-		err := r.ApplyDir(dsp, params, workflowControllerTemplatesDir)
+		// An invalid configuration is not applied, the workflow controller keeps running with the last valid one
+		configData, err := r.workflowControllerConfigData(ctx, dsp, params)
+		if err != nil {
+			return workflowControllerEnabled, err
+		}
+
+		log.Info("Applying WorkflowController Resources")
+		err = r.ApplyDir(dsp, params, workflowControllerTemplatesDir, workflowControllerConfigTransformer(configData))
 		if err != nil {
 			return workflowControllerEnabled, err
 		}
@@ -0,0 +1,402 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	workflowControllerConfigMapTemplate = "workflow-controller/configmap.yaml.tmpl"
+	// workflowControllerLegacyConfigKey holds the whole configuration as a single key, the legacy format of the
+	// Argo workflow controller ConfigMap
+	workflowControllerLegacyConfigKey = "config"
+)
+
+// artifactRepositoryProviders are the providers of the Argo artifact repository, exactly one of them is configured
+var artifactRepositoryProviders = []string{"s3", "gcs", "azure", "oss", "hdfs", "artifactory", "http"}
+
+var podGCStrategies = map[string]bool{
+	string(dspav1.PodGCOnPodCompletion):      true,
+	string(dspav1.PodGCOnPodSuccess):         true,
+	string(dspav1.PodGCOnWorkflowCompletion): true,
+	string(dspav1.PodGCOnWorkflowSuccess):    true,
+}
+
+// InvalidWorkflowControllerConfigError is returned when the workflow controller configuration resulting from the
+// DSPA and its custom ConfigMap cannot be applied. It is only fixed by the user, so it is reported on the
+// WorkflowControllerReady condition instead of being retried.
+type InvalidWorkflowControllerConfigError struct {
+	reason string
+}
+
+func (e *InvalidWorkflowControllerConfigError) Error() string {
+	return "invalid workflow controller configuration: " + e.reason
+}
+
+func invalidWorkflowControllerConfig(format string, args ...interface{}) error {
+	return &InvalidWorkflowControllerConfigError{reason: fmt.Sprintf(format, args...)}
+}
+
+// usesWorkflowControllerCustomConfig reports whether the DSPA merges the given ConfigMap into its workflow controller
+// configuration
+func usesWorkflowControllerCustomConfig(dsp *dspav1.DataSciencePipelinesApplication, configMapName string) bool {
+	return dsp.Spec.WorkflowController != nil && dsp.Spec.WorkflowController.CustomConfig == configMapName
+}
+
+// workflowControllerConfigData returns the data of the workflow controller ConfigMap. The configuration generated from
+// the templates is deep-merged with the customConfig ConfigMap, then with the config fields of the DSPA, and
+// validated. Each key holds the YAML of a top-level setting of the workflow controller configuration.
+func (r *DSPAReconciler) workflowControllerConfigData(ctx context.Context, dsp *dspav1.DataSciencePipelinesApplication,
+	params *DSPAParams) (map[string]string, error) {
+
+	manifest, err := config.Manifest(r.Client, r.TemplatesPath+workflowControllerConfigMapTemplate, params)
+	if err != nil {
+		return nil, fmt.Errorf("error loading template (%s) yaml: %w", workflowControllerConfigMapTemplate, err)
+	}
+	if len(manifest.Resources()) != 1 {
+		return nil, fmt.Errorf("template (%s) must contain a single ConfigMap", workflowControllerConfigMapTemplate)
+	}
+	data, _, err := unstructured.NestedStringMap(manifest.Resources()[0].Object, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := map[string]interface{}{}
+	for key, value := range data {
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, fmt.Errorf("error parsing the %s key of template (%s): %w", key, workflowControllerConfigMapTemplate, err)
+		}
+		configuration[key] = parsed
+	}
+	// Only the keys changed by the custom configuration are rendered again, the others keep the template formatting
+	changed := map[string]bool{}
+
+	workflowController := dsp.Spec.WorkflowController
+	if workflowController.CustomConfig != "" {
+		customConfig, err := r.workflowControllerCustomConfig(ctx, dsp.Namespace, workflowController.CustomConfig)
+		if err != nil {
+			return nil, err
+		}
+		mergeWorkflowControllerConfig(configuration, customConfig, changed)
+	}
+
+	typedConfig, err := typedWorkflowControllerConfig(workflowController.Config, configuration["artifactRepository"])
+	if err != nil {
+		return nil, err
+	}
+	mergeWorkflowControllerConfig(configuration, typedConfig, changed)
+
+	if err := validateWorkflowControllerConfig(configuration); err != nil {
+		return nil, err
+	}
+	// The runs of the DSPA are labeled with the instanceID assigned by the operator. The rendered value is compared
+	// rather than the parsed one, which is a number when the custom configuration sets an instanceID like 123.456.
+	if instanceID := params.WorkflowControllerInstanceID; instanceID != "" &&
+		(changed["instanceID"] || data["instanceID"] != strconv.Quote(instanceID)) {
+		return nil, invalidWorkflowControllerConfig("instanceID is assigned by the operator when instanceIDIsolation "+
+			"is enabled, it must not be set to %v", configuration["instanceID"])
+	}
+
+	for key := range changed {
+		if configuration[key] == nil {
+			continue
+		}
+		rendered, err := yaml.Marshal(configuration[key])
+		if err != nil {
+			return nil, err
+		}
+		data[key] = string(rendered)
+	}
+	return data, nil
+}
+
+// workflowControllerCustomConfig parses the keys of the customConfig ConfigMap
+func (r *DSPAReconciler) workflowControllerCustomConfig(ctx context.Context, namespace,
+	name string) (map[string]interface{}, error) {
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, configMap)
+	if apierrs.IsNotFound(err) {
+		return nil, invalidWorkflowControllerConfig("the customConfig ConfigMap %s was not found", name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	customConfig := map[string]interface{}{}
+	if legacyConfig, ok := configMap.Data[workflowControllerLegacyConfigKey]; ok {
+		if err := yaml.Unmarshal([]byte(legacyConfig), &customConfig); err != nil {
+			return nil, invalidWorkflowControllerConfig("the %s key of the customConfig ConfigMap %s is not a valid "+
+				"YAML mapping: %s", workflowControllerLegacyConfigKey, name, err)
+		}
+	}
+	for key, value := range configMap.Data {
+		if key == workflowControllerLegacyConfigKey {
+			continue
+		}
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, invalidWorkflowControllerConfig("the %s key of the customConfig ConfigMap %s is not valid YAML: %s",
+				key, name, err)
+		}
+		customConfig[key] = parsed
+	}
+	return customConfig, nil
+}
+
+// typedWorkflowControllerConfig returns the configuration set by the config fields of the DSPA. The keyFormat is set
+// on the provider of the artifact repository.
+func typedWorkflowControllerConfig(workflowControllerConfig *dspav1.WorkflowControllerConfig,
+	artifactRepository interface{}) (map[string]interface{}, error) {
+
+	typedConfig := map[string]interface{}{}
+	if workflowControllerConfig == nil {
+		return typedConfig, nil
+	}
+
+	typedArtifactRepository := map[string]interface{}{}
+	if workflowControllerConfig.ArchiveLogs != nil {
+		typedArtifactRepository["archiveLogs"] = *workflowControllerConfig.ArchiveLogs
+	}
+	if workflowControllerConfig.KeyFormat != "" {
+		repository, _ := artifactRepository.(map[string]interface{})
+		switch provider := configuredProviders(repository); {
+		case len(provider) != 1:
+			// Reported by the validation of the artifact repository
+		case provider[0] == "azure":
+			typedArtifactRepository["azure"] = map[string]interface{}{"blobNameFormat": workflowControllerConfig.KeyFormat}
+		case provider[0] == "s3", provider[0] == "gcs", provider[0] == "oss":
+			typedArtifactRepository[provider[0]] = map[string]interface{}{"keyFormat": workflowControllerConfig.KeyFormat}
+		default:
+			return nil, invalidWorkflowControllerConfig("keyFormat is not supported by the %s artifact repository",
+				provider[0])
+		}
+	}
+	if len(typedArtifactRepository) > 0 {
+		typedConfig["artifactRepository"] = typedArtifactRepository
+	}
+
+	if workflowControllerConfig.Parallelism != nil {
+		typedConfig["parallelism"] = float64(*workflowControllerConfig.Parallelism)
+	}
+	if workflowControllerConfig.NamespaceParallelism != nil {
+		typedConfig["namespaceParallelism"] = float64(*workflowControllerConfig.NamespaceParallelism)
+	}
+
+	workflowDefaults := map[string]interface{}{}
+	if workflowControllerConfig.WorkflowDefaults != nil && len(workflowControllerConfig.WorkflowDefaults.Raw) > 0 {
+		if err := json.Unmarshal(workflowControllerConfig.WorkflowDefaults.Raw, &workflowDefaults); err != nil {
+			return nil, invalidWorkflowControllerConfig("workflowDefaults must be an object: %s", err)
+		}
+	}
+	if workflowControllerConfig.PodGCStrategy != "" {
+		podGC := map[string]interface{}{"strategy": string(workflowControllerConfig.PodGCStrategy)}
+		mergeWorkflowControllerConfig(workflowDefaults,
+			map[string]interface{}{"spec": map[string]interface{}{"podGC": podGC}}, map[string]bool{})
+	}
+	if len(workflowDefaults) > 0 {
+		typedConfig["workflowDefaults"] = workflowDefaults
+	}
+
+	if workflowControllerConfig.ExecutorResources != nil {
+		resources, err := executorResources(workflowControllerConfig.ExecutorResources)
+		if err != nil {
+			return nil, err
+		}
+		typedConfig["executor"] = map[string]interface{}{"resources": resources}
+	}
+	return typedConfig, nil
+}
+
+// executorResources converts the resource requirements of the DSPA to the resources of the executor container
+func executorResources(requirements *dspav1.ResourceRequirements) (interface{}, error) {
+	toResourceList := func(resources *dspav1.Resources) corev1.ResourceList {
+		if resources == nil {
+			return nil
+		}
+		resourceList := corev1.ResourceList{}
+		if !resources.CPU.IsZero() {
+			resourceList[corev1.ResourceCPU] = resources.CPU
+		}
+		if !resources.Memory.IsZero() {
+			resourceList[corev1.ResourceMemory] = resources.Memory
+		}
+		return resourceList
+	}
+	encoded, err := json.Marshal(corev1.ResourceRequirements{
+		Limits:   toResourceList(requirements.Limits),
+		Requests: toResourceList(requirements.Requests),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resources interface{}
+	err = json.Unmarshal(encoded, &resources)
+	return resources, err
+}
+
+// mergeWorkflowControllerConfig deep-merges the overlay into the configuration and records the changed top-level
+// keys. Mappings are merged, other values are replaced and null values are ignored. The provider of the artifact
+// repository is replaced when the overlay configures another one.
+func mergeWorkflowControllerConfig(configuration, overlay map[string]interface{}, changed map[string]bool) {
+	for key, value := range overlay {
+		if value == nil {
+			continue
+		}
+		if key == "artifactRepository" {
+			configuration[key] = withoutReplacedProvider(configuration[key], value)
+		}
+		configuration[key] = mergeConfigValue(configuration[key], value)
+		changed[key] = true
+	}
+}
+
+// withoutReplacedProvider removes the provider of the artifact repository when the overlay configures another one
+func withoutReplacedProvider(artifactRepository, overlay interface{}) interface{} {
+	base, baseIsMap := artifactRepository.(map[string]interface{})
+	overlayMap, overlayIsMap := overlay.(map[string]interface{})
+	if !baseIsMap || !overlayIsMap || len(configuredProviders(overlayMap)) == 0 {
+		return artifactRepository
+	}
+	withoutProvider := make(map[string]interface{}, len(base))
+	for key, value := range base {
+		withoutProvider[key] = value
+	}
+	for _, provider := range artifactRepositoryProviders {
+		if _, ok := overlayMap[provider]; !ok {
+			delete(withoutProvider, provider)
+		}
+	}
+	return withoutProvider
+}
+
+func mergeConfigValue(base, overlay interface{}) interface{} {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overlayMap, overlayIsMap := overlay.(map[string]interface{})
+	if !baseIsMap || !overlayIsMap {
+		return overlay
+	}
+	merged := make(map[string]interface{}, len(baseMap)+len(overlayMap))
+	for key, value := range baseMap {
+		merged[key] = value
+	}
+	for key, value := range overlayMap {
+		if value == nil {
+			continue
+		}
+		merged[key] = mergeConfigValue(baseMap[key], value)
+	}
+	return merged
+}
+
+func configuredProviders(artifactRepository map[string]interface{}) []string {
+	var providers []string
+	for _, provider := range artifactRepositoryProviders {
+		if artifactRepository[provider] != nil {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// validateWorkflowControllerConfig validates the settings of the merged configuration the workflow controller would
+// otherwise fail to load
+func validateWorkflowControllerConfig(configuration map[string]interface{}) error {
+	artifactRepository, ok := configuration["artifactRepository"].(map[string]interface{})
+	if !ok {
+		return invalidWorkflowControllerConfig("artifactRepository must be a mapping")
+	}
+	if providers := configuredProviders(artifactRepository); len(providers) != 1 {
+		return invalidWorkflowControllerConfig("artifactRepository must configure exactly one of %s, found [%s]",
+			strings.Join(artifactRepositoryProviders, ", "), strings.Join(providers, ", "))
+	}
+	if archiveLogs, ok := artifactRepository["archiveLogs"]; ok {
+		if _, isBool := archiveLogs.(bool); !isBool {
+			return invalidWorkflowControllerConfig("artifactRepository.archiveLogs must be a boolean")
+		}
+	}
+
+	for _, key := range []string{"parallelism", "namespaceParallelism"} {
+		value, ok := configuration[key]
+		if !ok || value == nil {
+			continue
+		}
+		number, isNumber := value.(float64)
+		if !isNumber || number < 0 || number != math.Trunc(number) || number > math.MaxInt32 {
+			return invalidWorkflowControllerConfig("%s must be a non-negative integer", key)
+		}
+	}
+
+	if executor, ok := configuration["executor"]; ok && executor != nil {
+		encoded, err := json.Marshal(executor)
+		if err != nil {
+			return err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&corev1.Container{}); err != nil {
+			return invalidWorkflowControllerConfig("executor must be a container: %s", err)
+		}
+	}
+
+	if workflowDefaults, ok := configuration["workflowDefaults"]; ok && workflowDefaults != nil {
+		workflowDefaultsMap, isMap := workflowDefaults.(map[string]interface{})
+		if !isMap {
+			return invalidWorkflowControllerConfig("workflowDefaults must be a mapping")
+		}
+		strategy, found, err := unstructured.NestedFieldNoCopy(workflowDefaultsMap, "spec", "podGC", "strategy")
+		if err != nil {
+			return invalidWorkflowControllerConfig("workflowDefaults.spec.podGC must be a mapping")
+		}
+		if strategyString, isString := strategy.(string); found && strategy != nil && (!isString || !podGCStrategies[strategyString]) {
+			strategies := make([]string, 0, len(podGCStrategies))
+			for podGCStrategy := range podGCStrategies {
+				strategies = append(strategies, podGCStrategy)
+			}
+			sort.Strings(strategies)
+			return invalidWorkflowControllerConfig("workflowDefaults.spec.podGC.strategy must be one of %s",
+				strings.Join(strategies, ", "))
+		}
+	}
+	return nil
+}
+
+// workflowControllerConfigTransformer sets the data of the workflow controller ConfigMap
+func workflowControllerConfigTransformer(data map[string]string) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "ConfigMap" {
+			return nil
+		}
+		return unstructured.SetNestedStringMap(u.Object, data, "data")
+	}
+}
@@ -0,0 +1,217 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func workflowControllerConfigDSPA() *dspav1.DataSciencePipelinesApplication {
+	return &dspav1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1.DSPASpec{
+			PodToPodTLS:        testutil.BoolPtr(false),
+			APIServer:          &dspav1.APIServer{},
+			WorkflowController: &dspav1.WorkflowController{Deploy: true},
+			Database:           &dspav1.Database{MariaDB: &dspav1.MariaDB{Deploy: true}},
+			ObjectStorage:      &dspav1.ObjectStorage{Minio: &dspav1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+}
+
+func parseConfigKey(t *testing.T, data map[string]string, key string) map[string]interface{} {
+	parsed := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal([]byte(data[key]), &parsed))
+	return parsed
+}
+
+func TestWorkflowControllerConfigData(t *testing.T) {
+	dspa := workflowControllerConfigDSPA()
+	parallelism := int32(10)
+	dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+	dspa.Spec.WorkflowController.Config = &dspav1.WorkflowControllerConfig{
+		KeyFormat:        "artifacts/{{workflow.name}}/{{pod.name}}",
+		ArchiveLogs:      testutil.BoolPtr(true),
+		Parallelism:      &parallelism,
+		PodGCStrategy:    dspav1.PodGCOnWorkflowSuccess,
+		WorkflowDefaults: &runtime.RawExtension{Raw: []byte(`{"spec":{"ttlStrategy":{"secondsAfterCompletion":3600},"podGC":{"strategy":"OnPodCompletion"}}}`)},
+		ExecutorResources: &dspav1.ResourceRequirements{
+			Requests: &dspav1.Resources{CPU: resource.MustParse("100m")},
+			Limits:   &dspav1.Resources{Memory: resource.MustParse("1Gi")},
+		},
+	}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	customConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-workflow-controller-config", Namespace: "testnamespace"},
+		Data: map[string]string{
+			"artifactRepository":   "archiveLogs: false\ns3:\n  insecure: true\n",
+			"executor":             "imagePullPolicy: IfNotPresent\n",
+			"namespaceParallelism": "5",
+		},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, customConfig))
+
+	data, err := reconciler.workflowControllerConfigData(ctx, dspa, params)
+	require.NoError(t, err)
+
+	artifactRepository := parseConfigKey(t, data, "artifactRepository")
+	// The config fields take precedence over the custom ConfigMap
+	assert.Equal(t, true, artifactRepository["archiveLogs"])
+	s3 := artifactRepository["s3"].(map[string]interface{})
+	assert.Equal(t, "artifacts/{{workflow.name}}/{{pod.name}}", s3["keyFormat"])
+	assert.Equal(t, true, s3["insecure"])
+	assert.Equal(t, params.ObjectStorageConnection.Bucket, s3["bucket"])
+	assert.Equal(t, "10\n", data["parallelism"])
+	assert.Equal(t, "5\n", data["namespaceParallelism"])
+
+	workflowDefaults := parseConfigKey(t, data, "workflowDefaults")
+	assert.Equal(t, map[string]interface{}{
+		"ttlStrategy": map[string]interface{}{"secondsAfterCompletion": float64(3600)},
+		"podGC":       map[string]interface{}{"strategy": "OnWorkflowSuccess"},
+	}, workflowDefaults["spec"])
+
+	executor := parseConfigKey(t, data, "executor")
+	assert.Equal(t, map[string]interface{}{
+		"imagePullPolicy": "IfNotPresent",
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "100m"},
+			"limits":   map[string]interface{}{"memory": "1Gi"},
+		},
+	}, executor)
+}
+
+func TestWorkflowControllerConfigReplacesProvider(t *testing.T) {
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+	dspa.Spec.WorkflowController.Config = &dspav1.WorkflowControllerConfig{KeyFormat: "{{workflow.name}}/{{pod.name}}"}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	customConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-workflow-controller-config", Namespace: "testnamespace"},
+		Data: map[string]string{
+			"config": "artifactRepository:\n  azure:\n    endpoint: https://account.blob.core.windows.net\n    container: pipelines\n",
+		},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, customConfig))
+
+	data, err := reconciler.workflowControllerConfigData(ctx, dspa, params)
+	require.NoError(t, err)
+	artifactRepository := parseConfigKey(t, data, "artifactRepository")
+	assert.NotContains(t, artifactRepository, "s3")
+	assert.Equal(t, map[string]interface{}{
+		"endpoint":       "https://account.blob.core.windows.net",
+		"container":      "pipelines",
+		"blobNameFormat": "{{workflow.name}}/{{pod.name}}",
+	}, artifactRepository["azure"])
+}
+
+func TestInvalidWorkflowControllerConfig(t *testing.T) {
+	tests := map[string]struct {
+		customConfig  map[string]string
+		expectedError string
+	}{
+		"missing ConfigMap": {
+			expectedError: "invalid workflow controller configuration: the customConfig ConfigMap custom-workflow-controller-config was not found",
+		},
+		"invalid YAML": {
+			customConfig:  map[string]string{"executor": "imagePullPolicy: [IfNotPresent"},
+			expectedError: "invalid workflow controller configuration: the executor key of the customConfig ConfigMap custom-workflow-controller-config is not valid YAML",
+		},
+		"two providers": {
+			customConfig:  map[string]string{"artifactRepository": "s3:\n  bucket: b\ngcs:\n  bucket: b\n"},
+			expectedError: "invalid workflow controller configuration: artifactRepository must configure exactly one of s3, gcs, azure, oss, hdfs, artifactory, http, found [s3, gcs]",
+		},
+		"negative parallelism": {
+			customConfig:  map[string]string{"parallelism": "-1"},
+			expectedError: "invalid workflow controller configuration: parallelism must be a non-negative integer",
+		},
+		"unknown executor field": {
+			customConfig:  map[string]string{"executor": "imagePullPolicyy: Always"},
+			expectedError: "invalid workflow controller configuration: executor must be a container",
+		},
+		"invalid podGC strategy": {
+			customConfig:  map[string]string{"workflowDefaults": "spec:\n  podGC:\n    strategy: Never\n"},
+			expectedError: "invalid workflow controller configuration: workflowDefaults.spec.podGC.strategy must be one of OnPodCompletion, OnPodSuccess, OnWorkflowCompletion, OnWorkflowSuccess",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dspa := workflowControllerConfigDSPA()
+			dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+
+			ctx, params, reconciler := CreateNewTestObjects()
+			require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+			if test.customConfig != nil {
+				customConfig := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "custom-workflow-controller-config", Namespace: "testnamespace"},
+					Data:       test.customConfig,
+				}
+				require.NoError(t, reconciler.Client.Create(ctx, customConfig))
+			}
+
+			_, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
+			var invalidConfigErr *InvalidWorkflowControllerConfigError
+			require.True(t, errors.As(err, &invalidConfigErr))
+			assert.Contains(t, err.Error(), test.expectedError)
+
+			// The invalid configuration is not applied
+			created, err := reconciler.IsResourceCreated(ctx, &corev1.ConfigMap{}, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+			require.NoError(t, err)
+			assert.False(t, created)
+		})
+	}
+}
+
+func TestDeployWorkflowControllerConfig(t *testing.T) {
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.Config = &dspav1.WorkflowControllerConfig{ArchiveLogs: testutil.BoolPtr(true)}
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	_, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+
+	configMap := &corev1.ConfigMap{}
+	created, err := reconciler.IsResourceCreated(ctx, configMap, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, true, parseConfigKey(t, configMap.Data, "artifactRepository")["archiveLogs"])
+}
+
+func TestUsesWorkflowControllerCustomConfig(t *testing.T) {
+	dspa := workflowControllerConfigDSPA()
+	assert.False(t, usesWorkflowControllerCustomConfig(dspa, "custom-workflow-controller-config"))
+	dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+	assert.True(t, usesWorkflowControllerCustomConfig(dspa, "custom-workflow-controller-config"))
+	dspa.Spec.WorkflowController = nil
+	assert.False(t, usesWorkflowControllerCustomConfig(dspa, "custom-workflow-controller-config"))
+}
@@ -18,13 +18,19 @@ limitations under the License.
 package controllers
 
 import (
+	"strings"
 	"testing"
 
 	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestDeployWorkflowController(t *testing.T) {
@@ -73,7 +79,7 @@ func TestDeployWorkflowController(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Run test reconciliation
-	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.True(t, workflowControllerEnabled)
 
@@ -113,7 +119,7 @@ func TestDontDeployWorkflowController(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Run test reconciliation
-	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.False(t, workflowControllerEnabled)
 
@@ -172,7 +178,7 @@ func TestChangeManagementStateWorkflowController(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Run test reconciliation using default global managementState for WorkflowController
-	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.True(t, workflowControllerEnabled)
 
@@ -186,7 +192,7 @@ func TestChangeManagementStateWorkflowController(t *testing.T) {
 	viper.Set("DSPO.ArgoWorkflowsControllers", "{\"managementState\":\"Removed\"}")
 
 	// Run test reconciliation
-	workflowControllerEnabled, err = reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.False(t, workflowControllerEnabled)
 
@@ -200,7 +206,7 @@ func TestChangeManagementStateWorkflowController(t *testing.T) {
 	viper.Set("DSPO.ArgoWorkflowsControllers", "{\"managementState\":\"Managed\"}")
 
 	// Run test reconciliation
-	workflowControllerEnabled, err = reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.True(t, workflowControllerEnabled)
 
@@ -262,7 +268,7 @@ func TestBadManagementStateWorkflowController(t *testing.T) {
 	viper.Set("DSPO.ArgoWorkflowsControllers", "{\"managementState\":\"InvalidState\"}")
 
 	// Run test reconciliation
-	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.NotNil(t, err)
 	assert.False(t, workflowControllerEnabled)
 }
@@ -318,7 +324,7 @@ func TestManagementStateWorkflowControllerInvalidJSONRecovery(t *testing.T) {
 	viper.Set("DSPO.ArgoWorkflowsControllers", "{invalidJSON: 'foo")
 
 	// Run test reconciliation
-	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(dspa, params)
+	workflowControllerEnabled, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
 	assert.Nil(t, err)
 	assert.True(t, workflowControllerEnabled)
 
@@ -328,3 +334,93 @@ func TestManagementStateWorkflowControllerInvalidJSONRecovery(t *testing.T) {
 	assert.True(t, created)
 	assert.Nil(t, err)
 }
+
+func TestWorkflowControllerInstanceIDIsolation(t *testing.T) {
+	viper.Set("DSPO.ArgoWorkflowsControllers", config.DefaultArgoWorkflowsControllers)
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.InstanceIDIsolation = true
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Equal(t, "testnamespace.testdspa", params.WorkflowControllerInstanceID)
+	// The API server labels the runs through the compiled pipeline spec patch
+	assert.JSONEq(t, `{"metadata":{"labels":{"workflows.argoproj.io/controller-instanceid":"testnamespace.testdspa"}}}`,
+		params.CompiledPipelineSpecPatch)
+
+	_, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	require.NoError(t, err)
+	configMap := &corev1.ConfigMap{}
+	created, err := reconciler.IsResourceCreated(ctx, configMap, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, `"testnamespace.testdspa"`, configMap.Data["instanceID"])
+
+	// The instanceID cannot be changed by the custom configuration
+	dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+	customConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-workflow-controller-config", Namespace: "testnamespace"},
+		Data:       map[string]string{"instanceID": "shared"},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, customConfig))
+	_, err = reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	assert.EqualError(t, err, "invalid workflow controller configuration: instanceID is assigned by the operator "+
+		"when instanceIDIsolation is enabled, it must not be set to shared")
+
+	// The workflows of an external Argo Workflows installation are not labeled
+	dspa.Spec.WorkflowController.Mode = dspav1.WorkflowControllerModeExternal
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Empty(t, params.WorkflowControllerInstanceID)
+	assert.Empty(t, params.CompiledPipelineSpecPatch)
+}
+
+func TestWorkflowControllerNumericInstanceID(t *testing.T) {
+	viper.Set("DSPO.ArgoWorkflowsControllers", config.DefaultArgoWorkflowsControllers)
+	dspa := workflowControllerConfigDSPA()
+	dspa.Name, dspa.Namespace = "456", "123"
+	dspa.Spec.WorkflowController.InstanceIDIsolation = true
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	require.Equal(t, "123.456", params.WorkflowControllerInstanceID)
+	data, err := reconciler.workflowControllerConfigData(ctx, dspa, params)
+	require.NoError(t, err)
+	assert.Equal(t, `"123.456"`, data["instanceID"])
+
+	// An instanceID read as a number by the workflow controller is not the assigned one
+	dspa.Spec.WorkflowController.CustomConfig = "custom-workflow-controller-config"
+	customConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-workflow-controller-config", Namespace: "123"},
+		Data:       map[string]string{"instanceID": "123.456"},
+	}
+	require.NoError(t, reconciler.Client.Create(ctx, customConfig))
+	_, err = reconciler.workflowControllerConfigData(ctx, dspa, params)
+	assert.EqualError(t, err, "invalid workflow controller configuration: instanceID is assigned by the operator "+
+		"when instanceIDIsolation is enabled, it must not be set to 123.456")
+}
+
+func TestWorkflowControllerInstanceIDIsolationRequiresServiceCA(t *testing.T) {
+	viper.Set("DSPO.ArgoWorkflowsControllers", config.DefaultArgoWorkflowsControllers)
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.InstanceIDIsolation = true
+
+	ctx, params, reconciler := CreateNewTestObjects()
+	reconciler.ClusterCapabilities.Routes = false
+	require.NoError(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	_, err := reconciler.ReconcileWorkflowController(ctx, dspa, params)
+	var invalidConfigErr *InvalidWorkflowControllerConfigError
+	assert.ErrorAs(t, err, &invalidConfigErr)
+	created, err := reconciler.IsResourceCreated(ctx, &corev1.ConfigMap{}, "ds-pipeline-workflow-controller-testdspa", "testnamespace")
+	require.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestWorkflowControllerInstanceID(t *testing.T) {
+	assert.Equal(t, "testnamespace.testdspa", workflowControllerInstanceID("testnamespace", "testdspa"))
+
+	namespace := strings.Repeat("n", 40)
+	name := strings.Repeat("d", 40)
+	instanceID := workflowControllerInstanceID(namespace, name)
+	assert.Len(t, instanceID, 63)
+	assert.Empty(t, validation.IsValidLabelValue(instanceID))
+	assert.NotEqual(t, instanceID, workflowControllerInstanceID(namespace, name+"x"))
+}
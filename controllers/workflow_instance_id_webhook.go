@@ -0,0 +1,243 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var workflowInstanceIDWebhookTemplatesDir = "workflow-instanceid-webhook/"
+
+const (
+	// WorkflowInstanceIDWebhookPath is served by the webhook server of the operator
+	WorkflowInstanceIDWebhookPath = "/mutate-workflow-instanceid"
+
+	workflowInstanceIDWebhookName       = "ds-pipelines-workflow-instanceid"
+	workflowInstanceIDWebhookConfigName = "workflow-instanceid.datasciencepipelinesapplications.opendatahub.io"
+
+	// workflowInstanceIDWebhookCertificateRefresh is how often the certificate Secret is read again, so the
+	// certificates rotated by the OpenShift service CA are served
+	workflowInstanceIDWebhookCertificateRefresh = time.Minute
+
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;update;list;watch;patch;delete,resourceNames=workflow-instanceid.datasciencepipelinesapplications.opendatahub.io
+
+// WorkflowInstanceIDWebhookParams configures the webhook labeling the workflows of the DSPAs with instanceIDIsolation
+type WorkflowInstanceIDWebhookParams struct {
+	Name string
+	// Namespaces of the DSPAs isolating their workflows
+	Namespaces []string
+	// OperatorSelector selects the operator pods serving the webhook
+	OperatorSelector map[string]string
+}
+
+// SetupWorkflowInstanceIDWebhookWithManager sets up the controller of the webhook labeling the workflows of the DSPAs
+// with instanceIDIsolation. The webhook is shared by the DSPAs of the cluster, so every DSPA event is mapped to the
+// same request and its namespaces are computed from all the DSPAs by a single reconcile at a time.
+func (r *DSPAReconciler) SetupWorkflowInstanceIDWebhookWithManager(mgr ctrl.Manager) error {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: workflowInstanceIDWebhookName}}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("workflow-instanceid-webhook").
+		WatchesRawSource(source.Kind[client.Object](mgr.GetCache(), &dspav1.DataSciencePipelinesApplication{},
+			handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+				return []reconcile.Request{request}
+			}))).
+		Complete(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, r.ReconcileWorkflowInstanceIDWebhook(ctx, os.Getenv("DSPO_NAMESPACE"))
+		}))
+}
+
+// ReconcileWorkflowInstanceIDWebhook applies the webhook labeling the workflows of the DSPAs with instanceIDIsolation
+// when they are created, and removes it once no DSPA isolates its workflows. The webhook is served by the operator.
+func (r *DSPAReconciler) ReconcileWorkflowInstanceIDWebhook(ctx context.Context, dspoNamespace string) error {
+	log := r.Log.WithValues("namespace", dspoNamespace)
+
+	// The certificate of the webhook is issued by the OpenShift service CA, the DSPAs with instanceIDIsolation
+	// report an invalid configuration without it
+	if !r.ClusterCapabilities.Routes {
+		return nil
+	}
+
+	var namespaces []string
+	dspaList := &dspav1.DataSciencePipelinesApplicationList{}
+	if err := r.List(ctx, dspaList); err != nil {
+		return err
+	}
+	for i := range dspaList.Items {
+		if isolatedWorkflowControllerInstanceID(&dspaList.Items[i], log) != "" {
+			namespaces = append(namespaces, dspaList.Items[i].Namespace)
+		}
+	}
+	slices.Sort(namespaces)
+	namespaces = slices.Compact(namespaces)
+
+	if len(namespaces) == 0 {
+		return r.cleanUpWorkflowInstanceIDWebhook(ctx, dspoNamespace)
+	}
+
+	dataSciencePipelinesOperator := appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: dspoNamespace, Name: operatorName}, &dataSciencePipelinesOperator)
+	if err != nil {
+		return err
+	}
+	params := &DSPAParams{
+		DSPONamespace:      dspoNamespace,
+		WebhookAnnotations: r.WebhookAnnotations,
+		WorkflowInstanceIDWebhook: WorkflowInstanceIDWebhookParams{
+			Name:             workflowInstanceIDWebhookName,
+			Namespaces:       namespaces,
+			OperatorSelector: dataSciencePipelinesOperator.Spec.Selector.MatchLabels,
+		},
+	}
+
+	log.Info("Applying Workflow InstanceID Webhook Resources")
+	return r.ApplyDir(&dataSciencePipelinesOperator, params, workflowInstanceIDWebhookTemplatesDir)
+}
+
+func (r *DSPAReconciler) cleanUpWorkflowInstanceIDWebhook(ctx context.Context, namespace string) error {
+	err := r.DeleteResourceIfItExists(ctx, &admv1.MutatingWebhookConfiguration{},
+		types.NamespacedName{Name: workflowInstanceIDWebhookConfigName})
+	if err != nil {
+		return err
+	}
+	return r.DeleteResourceIfItExists(ctx, &corev1.Service{},
+		types.NamespacedName{Name: workflowInstanceIDWebhookName, Namespace: namespace})
+}
+
+// WorkflowInstanceIDLabeler labels the workflows created by the API Server and the ScheduledWorkflow controller of a
+// DSPA with instanceIDIsolation with the instanceID of its WorkflowController. The workflow controllers only see the
+// workflows labeled with their instanceID, so the label must be set when the workflow is created.
+type WorkflowInstanceIDLabeler struct {
+	Client client.Reader
+	Log    logr.Logger
+}
+
+func (l *WorkflowInstanceIDLabeler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create {
+		return admission.Allowed("")
+	}
+	dspa, err := l.creatorDSPA(ctx, req.Namespace, req.UserInfo.Username)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if dspa == nil {
+		return admission.Allowed("the workflow is not created by a DSPA")
+	}
+	instanceID := isolatedWorkflowControllerInstanceID(dspa, l.Log)
+	if instanceID == "" {
+		return admission.Allowed("the DSPA does not isolate its workflows")
+	}
+
+	workflow := &unstructured.Unstructured{}
+	if err := workflow.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	labels := workflow.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[argoControllerInstanceIDLabel] = instanceID
+	workflow.SetLabels(labels)
+	labeled, err := workflow.MarshalJSON()
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, labeled)
+}
+
+// creatorDSPA returns the DSPA whose API Server or ScheduledWorkflow controller service account created the workflow,
+// or nil when the workflow is created by another user
+func (l *WorkflowInstanceIDLabeler) creatorDSPA(ctx context.Context, namespace, username string) (*dspav1.DataSciencePipelinesApplication, error) {
+	serviceAccount, found := strings.CutPrefix(username, serviceAccountUsernamePrefix+namespace+":")
+	if !found {
+		return nil, nil
+	}
+	dspaList := &dspav1.DataSciencePipelinesApplicationList{}
+	if err := l.Client.List(ctx, dspaList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range dspaList.Items {
+		dspa := &dspaList.Items[i]
+		if serviceAccount == apiServerDefaultResourceNamePrefix+dspa.Name ||
+			serviceAccount == scheduledWorkflowDefaultResourceNamePrefix+dspa.Name {
+			return dspa, nil
+		}
+	}
+	return nil, nil
+}
+
+// WorkflowInstanceIDWebhookCertificate serves the certificate issued by the OpenShift service CA for the workflow
+// instanceID webhook. The Secret only exists while a DSPA isolates its workflows, so it is read when the webhook is
+// called rather than when the operator starts.
+type WorkflowInstanceIDWebhookCertificate struct {
+	Client    client.Reader
+	Namespace string
+
+	mu              sync.Mutex
+	certificate     *tls.Certificate
+	resourceVersion string
+	readAt          time.Time
+}
+
+func (c *WorkflowInstanceIDWebhookCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.certificate != nil && time.Since(c.readAt) < workflowInstanceIDWebhookCertificateRefresh {
+		return c.certificate, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	secret := &corev1.Secret{}
+	err := c.Client.Get(ctx, types.NamespacedName{Name: workflowInstanceIDWebhookName + "-tls", Namespace: c.Namespace}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the certificate of the workflow instanceID webhook: %w", err)
+	}
+	c.readAt = time.Now()
+	if c.certificate == nil || secret.ResourceVersion != c.resourceVersion {
+		certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate for the workflow instanceID webhook: %w", err)
+		}
+		c.certificate, c.resourceVersion = &certificate, secret.ResourceVersion
+	}
+	return c.certificate, nil
+}
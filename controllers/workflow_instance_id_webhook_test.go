@@ -0,0 +1,134 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func workflowCreateRequest(username string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Namespace: "testnamespace",
+		UserInfo:  authenticationv1.UserInfo{Username: username},
+		Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"argoproj.io/v1alpha1","kind":"Workflow",` +
+			`"metadata":{"generateName":"run-","namespace":"testnamespace","labels":{"pipeline/runid":"1"}}}`)},
+	}}
+}
+
+func TestWorkflowInstanceIDLabeler(t *testing.T) {
+	viper.Set("DSPO.ArgoWorkflowsControllers", config.DefaultArgoWorkflowsControllers)
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.InstanceIDIsolation = true
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	require.NoError(t, reconciler.Client.Create(ctx, dspa))
+	labeler := &WorkflowInstanceIDLabeler{Client: reconciler.Client, Log: reconciler.Log}
+
+	// The workflows created by the API Server and the ScheduledWorkflow controller are labeled
+	for _, serviceAccount := range []string{"ds-pipeline-testdspa", "ds-pipeline-scheduledworkflow-testdspa"} {
+		response := labeler.Handle(ctx, workflowCreateRequest("system:serviceaccount:testnamespace:"+serviceAccount))
+		require.True(t, response.Allowed)
+		require.Len(t, response.Patches, 1)
+		assert.Equal(t, "add", response.Patches[0].Operation)
+		assert.Equal(t, "/metadata/labels/workflows.argoproj.io~1controller-instanceid", response.Patches[0].Path)
+		assert.Equal(t, "testnamespace.testdspa", response.Patches[0].Value)
+	}
+
+	// The workflows of other users and of the service accounts of other namespaces are left as is
+	for _, username := range []string{
+		"system:serviceaccount:testnamespace:pipeline-runner-testdspa",
+		"system:serviceaccount:othernamespace:ds-pipeline-testdspa",
+		"kube:admin",
+	} {
+		response := labeler.Handle(ctx, workflowCreateRequest(username))
+		assert.True(t, response.Allowed)
+		assert.Empty(t, response.Patches)
+	}
+
+	// The workflows of a DSPA without instanceIDIsolation are left as is
+	dspa.Spec.WorkflowController.InstanceIDIsolation = false
+	require.NoError(t, reconciler.Client.Update(ctx, dspa))
+	response := labeler.Handle(ctx, workflowCreateRequest("system:serviceaccount:testnamespace:ds-pipeline-testdspa"))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+}
+
+func TestReconcileWorkflowInstanceIDWebhook(t *testing.T) {
+	viper.Set("DSPO.ArgoWorkflowsControllers", config.DefaultArgoWorkflowsControllers)
+	ctx, _, reconciler := CreateNewTestObjects()
+	operator := testutil.CreateTestDSPODeployment(testDSPONamespace)
+	require.NoError(t, reconciler.Client.Create(ctx, operator))
+
+	// The namespaces of every isolated DSPA are selected
+	other := workflowControllerConfigDSPA()
+	other.Namespace = "othernamespace"
+	other.Spec.WorkflowController.InstanceIDIsolation = true
+	require.NoError(t, reconciler.Client.Create(ctx, other))
+	dspa := workflowControllerConfigDSPA()
+	dspa.Spec.WorkflowController.InstanceIDIsolation = true
+	require.NoError(t, reconciler.Client.Create(ctx, dspa))
+	require.NoError(t, reconciler.ReconcileWorkflowInstanceIDWebhook(ctx, testDSPONamespace))
+	webhookConfig := &admv1.MutatingWebhookConfiguration{}
+	created, err := reconciler.IsResourceCreated(ctx, webhookConfig, workflowInstanceIDWebhookConfigName, "")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Len(t, webhookConfig.Webhooks, 1)
+	assert.Equal(t, []string{"othernamespace", "testnamespace"},
+		webhookConfig.Webhooks[0].NamespaceSelector.MatchExpressions[0].Values)
+	// The workflows are still created while the operator is unavailable
+	require.NotNil(t, webhookConfig.Webhooks[0].FailurePolicy)
+	assert.Equal(t, admv1.Ignore, *webhookConfig.Webhooks[0].FailurePolicy)
+	service := &corev1.Service{}
+	created, err = reconciler.IsResourceCreated(ctx, service, workflowInstanceIDWebhookName, testDSPONamespace)
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, operator.Spec.Selector.MatchLabels, service.Spec.Selector)
+
+	// The webhook is kept while another DSPA isolates its workflows
+	dspa.Spec.WorkflowController.InstanceIDIsolation = false
+	require.NoError(t, reconciler.Client.Update(ctx, dspa))
+	require.NoError(t, reconciler.ReconcileWorkflowInstanceIDWebhook(ctx, testDSPONamespace))
+	webhookConfig = &admv1.MutatingWebhookConfiguration{}
+	created, err = reconciler.IsResourceCreated(ctx, webhookConfig, workflowInstanceIDWebhookConfigName, "")
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, []string{"othernamespace"}, webhookConfig.Webhooks[0].NamespaceSelector.MatchExpressions[0].Values)
+
+	// The webhook is removed once no DSPA isolates its workflows
+	require.NoError(t, reconciler.Client.Delete(ctx, other))
+	require.NoError(t, reconciler.ReconcileWorkflowInstanceIDWebhook(ctx, testDSPONamespace))
+	created, err = reconciler.IsResourceCreated(ctx, &admv1.MutatingWebhookConfiguration{}, workflowInstanceIDWebhookConfigName, "")
+	require.NoError(t, err)
+	assert.False(t, created)
+	created, err = reconciler.IsResourceCreated(ctx, &corev1.Service{}, workflowInstanceIDWebhookName, testDSPONamespace)
+	require.NoError(t, err)
+	assert.False(t, created)
+}
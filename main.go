@@ -18,10 +18,12 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,7 +41,12 @@ import (
 	tlspkg "github.com/openshift/controller-runtime-common/pkg/tls"
 	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -47,6 +54,9 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	// to ensure that exec-entrypoint and run can make use of them.
 	admv1 "k8s.io/api/admissionregistration/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -130,6 +140,9 @@ func initConfig(configPath string) error {
 	if err := viper.BindEnv("Images.PipelinesComponents", "IMAGES_PIPELINES_COMPONENTS"); err != nil {
 		return err
 	}
+	if err := viper.BindEnv("Images.MariaDBBackup", "IMAGES_MARIADB_BACKUP"); err != nil {
+		return err
+	}
 	// Treat empty environment variables as set
 	viper.AllowEmptyEnv(true)
 
@@ -207,7 +220,8 @@ func main() {
 	// Build label selector for operator-managed resources using the dsp-version
 	// label that is applied to all resources created through manifestival templates.
 	// INVARIANT: every operator-managed child resource (Service, ServiceAccount,
-	// PVC, Role, RoleBinding, Route) is created via manifestival with
+	// PVC, Role, RoleBinding, CronJob, Job, PodDisruptionBudget, HorizontalPodAutoscaler,
+	// Route, Ingress, HTTPRoute, Certificate) is created via manifestival with
 	// AddLabelTransformer, so they always carry dsp-version. Do not introduce
 	// bare r.Get/r.List on filtered types for resources outside this path.
 	dspLabelReq, err := labels.NewRequirement(config.DSPVersionk8sLabel, selection.Exists, nil)
@@ -232,10 +246,28 @@ func main() {
 		setupLog.Error(err, "unable to resolve TLS profile, failing startup")
 		os.Exit(1)
 	}
-	tlsOpts := tlsResult.TLSOpts
 	profile := tlsResult.ProfileSpec
+	// The TLS profile is shared by the operator servers and the DSPA components, and follows the profile changes
+	tlsProfile := controllers.NewTLSProfile(profile)
+	tlsOpts := append(tlsResult.TLSOpts, dynamicTLSOpts(tlsProfile))
 	hasOpenShiftConfigAPI := tlsResult.HasOpenShiftConfig
 
+	// Detect the optional APIs used to expose the DSPA components and issue their certificates, the manager
+	// cannot watch kinds the cluster does not serve.
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restCfg)
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	clusterCapabilities, err := controllers.DetectClusterCapabilities(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to detect cluster capabilities")
+		os.Exit(1)
+	}
+	setupLog.Info("Detected cluster capabilities", "routes", clusterCapabilities.Routes,
+		"ingresses", clusterCapabilities.Ingresses, "httpRoutes", clusterCapabilities.HTTPRoutes,
+		"certificates", clusterCapabilities.Certificates)
+
 	// Fetch the TLS adherence policy (only meaningful on OpenShift clusters with the config API)
 	tlsAdherenceFetched := false
 	var tlsAdherence configv1.TLSAdherencePolicy
@@ -248,6 +280,16 @@ func main() {
 		tlsAdherenceFetched = true
 	}
 
+	// The webhook server only serves the webhook labeling the workflows of the DSPAs with instanceIDIsolation, its
+	// certificate is issued by the OpenShift service CA once such a DSPA exists
+	workflowInstanceIDWebhookCertificate := &controllers.WorkflowInstanceIDWebhookCertificate{
+		Client:    bootstrapClient,
+		Namespace: dspoNamespace,
+	}
+	webhookTLSOpts := append(slices.Clone(tlsOpts), func(c *tls.Config) {
+		c.GetCertificate = workflowInstanceIDWebhookCertificate.GetCertificate
+	})
+
 	mgrOpts := ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -256,7 +298,7 @@ func main() {
 		},
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port:    9443,
-			TLSOpts: tlsOpts,
+			TLSOpts: webhookTLSOpts,
 		}),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -278,12 +320,15 @@ func main() {
 				// Note: Deployment is intentionally NOT filtered because the
 				// operator looks up its own Deployment (which lacks the
 				// dsp-version label) during webhook reconciliation.
-				&corev1.Service{}:               dspFilter,
-				&corev1.ServiceAccount{}:        dspFilter,
-				&corev1.PersistentVolumeClaim{}: dspFilter,
-				&rbacv1.Role{}:                  dspFilter,
-				&rbacv1.RoleBinding{}:           dspFilter,
-				&routev1.Route{}:                dspFilter,
+				&corev1.Service{}:                        dspFilter,
+				&corev1.ServiceAccount{}:                 dspFilter,
+				&corev1.PersistentVolumeClaim{}:          dspFilter,
+				&rbacv1.Role{}:                           dspFilter,
+				&rbacv1.RoleBinding{}:                    dspFilter,
+				&batchv1.CronJob{}:                       dspFilter,
+				&batchv1.Job{}:                           dspFilter,
+				&policyv1.PodDisruptionBudget{}:          dspFilter,
+				&autoscalingv2.HorizontalPodAutoscaler{}: dspFilter,
 				// Pod is watched via WatchesRawSource with a handler that filters
 				// by component=data-science-pipelines label, so we can scope the
 				// informer to only cache pods with that label.
@@ -310,11 +355,40 @@ func main() {
 		},
 	}
 
+	if clusterCapabilities.Routes {
+		mgrOpts.Cache.ByObject[&routev1.Route{}] = dspFilter
+	}
+	if clusterCapabilities.Ingresses {
+		mgrOpts.Cache.ByObject[&networkingv1.Ingress{}] = dspFilter
+	}
+	if clusterCapabilities.HTTPRoutes {
+		httpRoute := &unstructured.Unstructured{}
+		httpRoute.SetGroupVersionKind(controllers.HTTPRouteGVK)
+		mgrOpts.Cache.ByObject[httpRoute] = dspFilter
+	}
+	if clusterCapabilities.Certificates {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(controllers.CertificateGVK)
+		mgrOpts.Cache.ByObject[certificate] = dspFilter
+	}
+
 	// MLflow CRs are read during reconcile; always bypass the client cache so
 	// behavior does not depend on whether the CRD existed at operator startup.
 	mgrOpts.Client.Cache.DisableFor = append(mgrOpts.Client.Cache.DisableFor, &mlflowv1.MLflow{})
 	setupLog.Info("MLflow reads use direct API access (bypass controller-runtime client cache)")
 
+	// The endpoints of the kubernetes Service are watched for the egress NetworkPolicy, without caching every
+	// EndpointSlice of the cluster
+	mgrOpts.Cache.ByObject[&discoveryv1.EndpointSlice{}] = cache.ByObject{
+		Namespaces: map[string]cache.Config{metav1.NamespaceDefault: {}},
+		Label: labels.SelectorFromSet(labels.Set{
+			discoveryv1.LabelServiceName: controllers.KubernetesServiceName,
+		}),
+	}
+
+	// The cache only holds the pipelineversions webhook, the workflow instanceID webhook is read directly
+	mgrOpts.Client.Cache.DisableFor = append(mgrOpts.Client.Cache.DisableFor, &admv1.MutatingWebhookConfiguration{})
+
 	mgr, err := ctrl.NewManager(restCfg, mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -342,7 +416,7 @@ func main() {
 		}
 	}
 
-	if err = (&controllers.DSPAReconciler{
+	dspaReconciler := &controllers.DSPAReconciler{
 		Client:                  mgr.GetClient(),
 		APIReader:               mgr.GetAPIReader(),
 		MLflowEndpointCacheTTL:  controllers.DefaultMLflowEndpointCacheTTL,
@@ -352,10 +426,24 @@ func main() {
 		MaxConcurrentReconciles: maxConcurrentReconciles,
 		WebhookAnnotations:      webhookAnnotations,
 		AllowedRegistries:       allowedRegistries,
-	}).SetupWithManager(mgr); err != nil {
+		ClusterCapabilities:     clusterCapabilities,
+		TLSProfile:              tlsProfile,
+	}
+	if err = dspaReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DSPAParams")
 		os.Exit(1)
 	}
+	if err = dspaReconciler.SetupWorkflowInstanceIDWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WorkflowInstanceIDWebhook")
+		os.Exit(1)
+	}
+
+	mgr.GetWebhookServer().Register(controllers.WorkflowInstanceIDWebhookPath, &webhook.Admission{
+		Handler: &controllers.WorkflowInstanceIDLabeler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("workflow-instanceid-webhook"),
+		},
+	})
 
 	//+kubebuilder:scaffold:builder
 
@@ -368,16 +456,22 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Register SecurityProfileWatcher on OpenShift: cancel context on TLS profile change so pod restarts
+	// Register SecurityProfileWatcher on OpenShift: a TLS profile change is applied to the operator servers and
+	// re-reconciles all DSPAs, an adherence policy change cancels the context so the pod restarts
 	ctx, cancel := context.WithCancel(ctrl.SetupSignalHandler())
 	defer cancel()
 	if hasOpenShiftConfigAPI {
 		watcher := &tlspkg.SecurityProfileWatcher{
 			Client:                mgr.GetClient(),
 			InitialTLSProfileSpec: profile,
-			OnProfileChange: func(_ context.Context, _, _ configv1.TLSProfileSpec) {
-				setupLog.Info("TLS profile changed, initiating graceful shutdown to reload")
-				cancel()
+			OnProfileChange: func(_ context.Context, _, newProfile configv1.TLSProfileSpec) {
+				if _, unsupportedCiphers := tlspkg.NewTLSConfigFromProfile(newProfile); len(unsupportedCiphers) > 0 {
+					setupLog.Info("some ciphers from TLS profile are not supported by Go", "unsupported", unsupportedCiphers)
+				}
+				if err := dspaReconciler.OnProfileChange(ctx, newProfile); err != nil {
+					setupLog.Error(err, "TLS profile changed but the DSPAs could not be listed, initiating graceful shutdown to reload")
+					cancel()
+				}
 			},
 		}
 		if tlsAdherenceFetched {
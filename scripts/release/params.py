@@ -31,6 +31,11 @@ TAGGED_REPOS = {
         "TAG": "odh-stable",
         "REPO": "odh-pipelines-components",
     },
+    "IMAGES_MARIADB_BACKUP": {
+        "TAG": "latest",
+        "REPO": "mc",
+        "ORG": "minio",
+    },
 }
 
 STATIC_REPOS = {
@@ -116,7 +121,8 @@ def generate_params(args):
     for image in TAGGED_REPOS:
         target_repo = {image: TAGGED_REPOS[image]["REPO"]}
         target_tag = TAGGED_REPOS[image]["TAG"]
-        fetch_images(target_repo, overrides, env_var_lines, quay_org, target_tag)
+        target_org = TAGGED_REPOS[image].get("ORG", quay_org)
+        fetch_images(target_repo, overrides, env_var_lines, target_org, target_tag)
 
     static_vars(STATIC_REPOS, overrides, env_var_lines)
     static_vars(OTHER_OPTIONS, overrides, env_var_lines)
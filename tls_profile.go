@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers"
 	configv1 "github.com/openshift/api/config/v1"
 	tlspkg "github.com/openshift/controller-runtime-common/pkg/tls"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,7 +37,7 @@ func fetchTLSProfile(ctx context.Context, cli client.Client) (*tlsResult, error)
 			apierrors.IsTooManyRequests(err):
 			l.Info("Transient API error reading TLS profile, using Intermediate TLS profile as fallback", "error", err)
 			// Mark OpenShift config as present so SecurityProfileWatcher still registers.
-			// When the API recovers, the watcher will detect the real profile and trigger a restart.
+			// When the API recovers, the watcher will detect the real profile and apply it.
 			result.HasOpenShiftConfig = true
 		default:
 			return nil, fmt.Errorf("failed to fetch TLS profile: %w", err)
@@ -63,3 +64,17 @@ func fetchTLSProfile(ctx context.Context, cli client.Client) (*tlsResult, error)
 
 	return result, nil
 }
+
+// dynamicTLSOpts applies the current TLS profile to every new connection of the operator servers, so a profile
+// change is picked up without restarting the operator.
+func dynamicTLSOpts(tlsProfile *controllers.TLSProfile) func(*tls.Config) {
+	return func(c *tls.Config) {
+		c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := c.Clone()
+			config.GetConfigForClient = nil
+			tlsConfigFn, _ := tlspkg.NewTLSConfigFromProfile(tlsProfile.Get())
+			tlsConfigFn(config)
+			return config, nil
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"testing"
 
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers"
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -143,3 +144,21 @@ func TestFetchTLSProfile_AllCiphersUnsupported(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "all 2 ciphers in TLS profile are unsupported by Go")
 }
+
+func TestDynamicTLSOpts(t *testing.T) {
+	tlsProfile := controllers.NewTLSProfile(*configv1.TLSProfiles[configv1.TLSProfileIntermediateType])
+	cfg := &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	dynamicTLSOpts(tlsProfile)(cfg)
+	require.NotNil(t, cfg.GetConfigForClient)
+
+	clientCfg, err := cfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), clientCfg.MinVersion)
+	assert.Equal(t, cfg.NextProtos, clientCfg.NextProtos)
+
+	// New connections use the updated profile
+	tlsProfile.Set(*configv1.TLSProfiles[configv1.TLSProfileModernType])
+	clientCfg, err = cfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), clientCfg.MinVersion)
+}